@@ -5,7 +5,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 	"together-backend/internal/router"
+	"together-backend/internal/scheduler"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/cors"
@@ -17,6 +19,7 @@ func initRouter(port string) {
 	corsWrapper := cors.New(cors.Options{
 		AllowedMethods: []string{"GET", "POST", "DELETE", "PUT"},
 		AllowedHeaders: []string{"Content-Type", "Authorization", "Origin", "Accept", "*"},
+		ExposedHeaders: []string{"ETag", "Content-Disposition"},
 	})
 
 	if port == "" {
@@ -38,5 +41,6 @@ func main() {
 	}
 	port := os.Getenv("PORT")
 
+	scheduler.StartEventPublisher(time.Minute)
 	initRouter(port)
 }
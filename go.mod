@@ -10,7 +10,9 @@ require (
 	github.com/joho/godotenv v1.4.0
 	github.com/rs/cors v1.8.0
 	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa
+	golang.org/x/text v0.3.7
 	gorm.io/driver/mysql v1.1.3
+	gorm.io/driver/postgres v1.3.10
 	gorm.io/gorm v1.23.7
 )
 
@@ -29,6 +31,4 @@ require (
 	github.com/jackc/pgx/v4 v4.17.2 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.4 // indirect
-	golang.org/x/text v0.3.7 // indirect
-	gorm.io/driver/postgres v1.3.10 // indirect
 )
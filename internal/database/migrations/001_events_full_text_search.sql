@@ -0,0 +1,19 @@
+-- PostgreSQL full-text search for events.
+-- Titles weigh more than content, content more than detail_location.
+-- Accents are stripped on both sides so "ha noi" matches "Hà Nội".
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS together_unaccent;
+CREATE TEXT SEARCH CONFIGURATION together_unaccent (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION together_unaccent
+  ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+
+ALTER TABLE events DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE events ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('together_unaccent', coalesce(title, '')), 'A') ||
+  setweight(to_tsvector('together_unaccent', coalesce(content, '')), 'B') ||
+  setweight(to_tsvector('together_unaccent', coalesce(detail_location, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector);
@@ -0,0 +1,3 @@
+-- Attendance tracking, set when an organizer scans the attendee's ticket.
+
+ALTER TABLE user_events ADD COLUMN IF NOT EXISTS checked_in_at timestamptz;
@@ -0,0 +1,18 @@
+-- Event lifecycle (draft, published, postponed, cancelled, completed)
+-- and in-app notifications sent to attendees when it changes.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'published';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status_reason text NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status_changed_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_events_status ON events (status);
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users (id),
+  event_id bigint REFERENCES events (id),
+  type varchar(32) NOT NULL,
+  message text NOT NULL,
+  read_at timestamptz,
+  created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id, created_at DESC, id DESC);
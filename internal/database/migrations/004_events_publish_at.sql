@@ -0,0 +1,4 @@
+-- Scheduled publishing of draft events.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS publish_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_events_draft_publish_at ON events (publish_at) WHERE status = 'draft';
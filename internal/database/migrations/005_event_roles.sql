@@ -0,0 +1,12 @@
+-- Event staff. The owner stays in events.created_by, co-organizers and
+-- moderators are listed here.
+
+CREATE TABLE IF NOT EXISTS event_roles (
+  event_id bigint NOT NULL REFERENCES events (id),
+  user_id bigint NOT NULL REFERENCES users (id),
+  role varchar(16) NOT NULL,
+  invited_by bigint REFERENCES users (id),
+  created_at timestamptz,
+  PRIMARY KEY (event_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_event_roles_user_id ON event_roles (user_id);
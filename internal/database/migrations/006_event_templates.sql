@@ -0,0 +1,17 @@
+-- Reusable per-user event templates.
+
+CREATE TABLE IF NOT EXISTS event_templates (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users (id),
+  name text NOT NULL,
+  title text,
+  content text,
+  duration integer NOT NULL DEFAULT 0,
+  location integer,
+  detail_location text,
+  created_at timestamptz,
+  updated_at timestamptz,
+  deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_event_templates_user_id ON event_templates (user_id);
+CREATE INDEX IF NOT EXISTS idx_event_templates_deleted_at ON event_templates (deleted_at);
@@ -0,0 +1,11 @@
+-- One row per event update with the changed fields and their old/new values.
+
+CREATE TABLE IF NOT EXISTS event_revisions (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events (id),
+  version integer NOT NULL,
+  changed_by bigint REFERENCES users (id),
+  changes jsonb NOT NULL DEFAULT '[]',
+  created_at timestamptz,
+  UNIQUE (event_id, version)
+);
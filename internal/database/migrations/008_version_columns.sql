@@ -0,0 +1,4 @@
+-- Row versions for optimistic concurrency, exposed as ETag.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
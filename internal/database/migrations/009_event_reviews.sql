@@ -0,0 +1,14 @@
+-- One rating and review per attendee per event, left after the event ends.
+
+CREATE TABLE IF NOT EXISTS event_reviews (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events (id),
+  user_id bigint NOT NULL REFERENCES users (id),
+  rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  content text NOT NULL DEFAULT '',
+  created_at timestamptz,
+  updated_at timestamptz,
+  UNIQUE (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_reviews_created_at_idx ON event_reviews (event_id, created_at DESC, id DESC);
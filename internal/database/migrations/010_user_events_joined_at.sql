@@ -0,0 +1,4 @@
+-- When an attendee joined, used by the roster export. Existing rows stay NULL.
+
+ALTER TABLE user_events ADD COLUMN IF NOT EXISTS joined_at timestamptz;
+ALTER TABLE user_events ALTER COLUMN joined_at SET DEFAULT now();
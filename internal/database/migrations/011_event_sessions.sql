@@ -0,0 +1,28 @@
+-- Sessions inside an event, each with its own capacity and signups.
+
+CREATE TABLE IF NOT EXISTS event_sessions (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events (id),
+  title text NOT NULL,
+  speaker text NOT NULL DEFAULT '',
+  room text NOT NULL DEFAULT '',
+  start_time timestamptz NOT NULL,
+  end_time timestamptz NOT NULL,
+  capacity integer NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+  created_at timestamptz,
+  updated_at timestamptz,
+  deleted_at timestamptz,
+  CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS event_sessions_event_id_idx ON event_sessions (event_id, start_time);
+CREATE INDEX IF NOT EXISTS event_sessions_deleted_at_idx ON event_sessions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS session_signups (
+  session_id bigint NOT NULL REFERENCES event_sessions (id),
+  user_id bigint NOT NULL REFERENCES users (id),
+  created_at timestamptz,
+  PRIMARY KEY (session_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS session_signups_user_id_idx ON session_signups (user_id);
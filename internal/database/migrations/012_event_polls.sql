@@ -0,0 +1,40 @@
+-- Polls attached to an event; date polls propose new event times.
+
+CREATE TABLE IF NOT EXISTS event_polls (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events (id),
+  created_by bigint NOT NULL REFERENCES users (id),
+  question text NOT NULL,
+  type text NOT NULL DEFAULT 'choice' CHECK (type IN ('choice', 'date')),
+  multiple_choice boolean NOT NULL DEFAULT false,
+  anonymous boolean NOT NULL DEFAULT false,
+  closes_at timestamptz,
+  applied_at timestamptz,
+  created_at timestamptz,
+  updated_at timestamptz,
+  deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS event_polls_event_id_idx ON event_polls (event_id);
+CREATE INDEX IF NOT EXISTS event_polls_deleted_at_idx ON event_polls (deleted_at);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+  id bigserial PRIMARY KEY,
+  poll_id bigint NOT NULL REFERENCES event_polls (id),
+  position integer NOT NULL,
+  label text NOT NULL,
+  start_time timestamptz,
+  end_time timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS poll_options_poll_id_idx ON poll_options (poll_id, position);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+  poll_id bigint NOT NULL REFERENCES event_polls (id),
+  option_id bigint NOT NULL REFERENCES poll_options (id),
+  user_id bigint NOT NULL REFERENCES users (id),
+  created_at timestamptz,
+  PRIMARY KEY (option_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_poll_id_idx ON poll_votes (poll_id, user_id);
@@ -0,0 +1,5 @@
+-- One level of replies: a reply points at the top-level comment of its thread.
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id bigint REFERENCES comments (id);
+
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id, created_at, id);
@@ -0,0 +1,13 @@
+-- Comment edits: edited_at marks edited comments, prior contents are kept.
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+  id bigserial PRIMARY KEY,
+  comment_id bigint NOT NULL REFERENCES comments (id),
+  content text NOT NULL,
+  edited_by bigint REFERENCES users (id),
+  created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comment_revisions_comment_id_idx ON comment_revisions (comment_id, created_at);
@@ -0,0 +1,13 @@
+-- Emoji reactions on events and comments, one per emoji per user.
+
+CREATE TABLE IF NOT EXISTS reactions (
+  id bigserial PRIMARY KEY,
+  target_type text NOT NULL CHECK (target_type IN ('event', 'comment')),
+  target_id bigint NOT NULL,
+  user_id bigint NOT NULL REFERENCES users (id),
+  emoji text NOT NULL,
+  created_at timestamptz,
+  UNIQUE (target_type, target_id, user_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS reactions_target_idx ON reactions (target_type, target_id, emoji);
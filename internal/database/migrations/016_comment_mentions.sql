@@ -0,0 +1,13 @@
+-- @mentions of attendees in comments, with their position in the content.
+
+CREATE TABLE IF NOT EXISTS comment_mentions (
+  id bigserial PRIMARY KEY,
+  comment_id bigint NOT NULL REFERENCES comments (id),
+  user_id bigint NOT NULL REFERENCES users (id),
+  start_offset integer NOT NULL,
+  length integer NOT NULL,
+  created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comment_mentions_comment_id_idx ON comment_mentions (comment_id, start_offset);
+CREATE INDEX IF NOT EXISTS comment_mentions_user_id_idx ON comment_mentions (user_id);
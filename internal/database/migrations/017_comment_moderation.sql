@@ -0,0 +1,22 @@
+-- Hiding and pinning of comments by event staff, the site moderator role,
+-- and an audit trail of moderation actions.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'moderator'));
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at timestamptz;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS pinned_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS comments_pinned_idx ON comments (event_id, pinned_at) WHERE pinned_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS comment_moderation_logs (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events (id),
+  comment_id bigint NOT NULL,
+  actor_id bigint NOT NULL REFERENCES users (id),
+  action text NOT NULL CHECK (action IN ('hide', 'unhide', 'pin', 'unpin', 'delete')),
+  reason text NOT NULL DEFAULT '',
+  content text NOT NULL DEFAULT '',
+  created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comment_moderation_logs_event_idx ON comment_moderation_logs (event_id, created_at DESC, id DESC);
@@ -0,0 +1,19 @@
+-- Content held back by the moderation filter until a moderator reviews it.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS hidden_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS moderation_items (
+  id bigserial PRIMARY KEY,
+  target_type text NOT NULL CHECK (target_type IN ('event', 'comment')),
+  target_id bigint NOT NULL,
+  event_id bigint NOT NULL REFERENCES events (id),
+  author_id bigint NOT NULL REFERENCES users (id),
+  source text NOT NULL,
+  reason text NOT NULL DEFAULT '',
+  status text NOT NULL DEFAULT 'pending',
+  created_at timestamptz,
+  resolved_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS moderation_items_status_idx ON moderation_items (status, created_at, id);
+CREATE INDEX IF NOT EXISTS moderation_items_target_idx ON moderation_items (target_type, target_id);
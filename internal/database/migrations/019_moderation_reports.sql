@@ -0,0 +1,32 @@
+-- User reports feeding the moderation queue, item assignment and
+-- resolution, and user suspensions.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until timestamptz;
+
+ALTER TABLE moderation_items ALTER COLUMN event_id DROP NOT NULL;
+ALTER TABLE moderation_items DROP CONSTRAINT IF EXISTS moderation_items_target_type_check;
+ALTER TABLE moderation_items ADD CONSTRAINT moderation_items_target_type_check CHECK (target_type IN ('event', 'comment', 'user'));
+ALTER TABLE moderation_items ADD COLUMN IF NOT EXISTS target_hidden boolean NOT NULL DEFAULT false;
+ALTER TABLE moderation_items ADD COLUMN IF NOT EXISTS assignee_id bigint REFERENCES users (id);
+ALTER TABLE moderation_items ADD COLUMN IF NOT EXISTS resolution text NOT NULL DEFAULT '';
+ALTER TABLE moderation_items ADD COLUMN IF NOT EXISTS resolved_by bigint REFERENCES users (id);
+ALTER TABLE moderation_items ADD COLUMN IF NOT EXISTS note text NOT NULL DEFAULT '';
+
+-- items raised by the filter so far all hid their target
+UPDATE moderation_items SET target_hidden = true WHERE source = 'filter';
+
+-- one open item per target collects all reports of it
+CREATE UNIQUE INDEX IF NOT EXISTS moderation_items_open_target_idx ON moderation_items (target_type, target_id) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS moderation_reports (
+  id bigserial PRIMARY KEY,
+  item_id bigint NOT NULL REFERENCES moderation_items (id),
+  reporter_id bigint NOT NULL REFERENCES users (id),
+  category text NOT NULL,
+  details text NOT NULL DEFAULT '',
+  created_at timestamptz,
+  UNIQUE (item_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS moderation_reports_category_idx ON moderation_reports (category, item_id);
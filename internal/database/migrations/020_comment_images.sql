@@ -0,0 +1,15 @@
+-- Images attached to comments, shown with the event's own images in its gallery.
+
+CREATE TABLE IF NOT EXISTS comment_images (
+  id bigserial PRIMARY KEY,
+  comment_id bigint NOT NULL REFERENCES comments (id),
+  event_id bigint NOT NULL REFERENCES events (id),
+  image_url text NOT NULL,
+  created_at timestamptz,
+  updated_at timestamptz,
+  deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS comment_images_comment_id_idx ON comment_images (comment_id);
+CREATE INDEX IF NOT EXISTS comment_images_event_id_idx ON comment_images (event_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS comment_images_deleted_at_idx ON comment_images (deleted_at);
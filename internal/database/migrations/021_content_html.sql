@@ -0,0 +1,5 @@
+-- Markdown content is rendered to HTML once, when it is written. Rows saved
+-- before this migration are rendered when they are loaded until next edited.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS content_html text;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS content_html text;
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
 	"together-backend/internal/database"
+	"together-backend/internal/models"
 	"together-backend/internal/repositories"
+	"together-backend/internal/transfers"
 	"together-backend/internal/usecases"
 
 	"github.com/gorilla/mux"
@@ -17,22 +21,22 @@ var (
 
 const SIZE int = 8
 
+// maxCommentBodySize caps a comment request body, well above what
+// models.MaxCommentLength allows.
+const maxCommentBodySize = 64 << 10
+
+// maxCommentUploadSize caps a multipart comment with its images.
+const maxCommentUploadSize = models.MaxCommentImages*usecases.MAX_UPLOAD_SIZE + maxCommentBodySize
+
 func GetCommentsByEventId(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var (
-		err         error
-		commentPage int = 1
-	)
-	queries := r.URL.Query()
-	if len(queries["comment_page"]) > 0 {
-		commentPage, err = strconv.Atoi(queries["comment_page"][0])
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{
-				"message": "failed to parse comment page",
-			})
-			return
-		}
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "comment_page", SIZE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse comment page",
+		})
+		return
 	}
 
 	params := mux.Vars(r)
@@ -45,9 +49,11 @@ func GetCommentsByEventId(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comments, total, err := commentUsercase.GetCommentsByEventIdUsecase(eventId, commentPage, SIZE)
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	comments, pageInfo, total, err := commentUsercase.GetCommentsByEventIdUsecase(eventId, viewerId, pagination)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(listErrorStatus(err))
 		json.NewEncoder(w).Encode(map[string]string{
 			"message": err.Error(),
 		})
@@ -60,15 +66,93 @@ func GetCommentsByEventId(w http.ResponseWriter, r *http.Request) {
 		"comments":     comments,
 		"event_id":     eventId,
 		"total":        total,
-		"comment_page": commentPage,
+		"comment_page": pagination.Page,
+		"limit":        pageInfo.Limit,
+		"next_cursor":  pageInfo.NextCursor,
+		"prev_cursor":  pageInfo.PrevCursor,
+	})
+}
+
+func GetCommentReplies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "reply_page", SIZE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse reply page",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	commentId, err := strconv.Atoi(params["comment_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	replies, pageInfo, err := commentUsercase.GetRepliesUsecase(commentId, eventId, viewerId, pagination)
+	if err != nil {
+		w.WriteHeader(listErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get replies successfully",
+		"replies":     replies,
+		"event_id":    eventId,
+		"comment_id":  commentId,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
 	})
 }
 
 func CreateComment(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	var reqBodyComment *usecases.ReqBodyComment
-	err := json.NewDecoder(r.Body).Decode(&reqBodyComment)
+	var (
+		reqBodyComment *usecases.ReqBodyComment
+		images         []*multipart.FileHeader
+		err            error
+	)
+	// comments with photos come as multipart, plain ones as JSON
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, maxCommentUploadSize)
+		err = r.ParseMultipartForm(32 << 20)
+		if err == nil {
+			reqBodyComment, err = transfers.ParseRequestComment(r.MultipartForm)
+			images = r.MultipartForm.File["images"]
+		}
+	} else {
+		r.Body = http.MaxBytesReader(w, r.Body, maxCommentBodySize)
+		err = json.NewDecoder(r.Body).Decode(&reqBodyComment)
+	}
+	if err != nil && strings.HasSuffix(err.Error(), "http: request body too large") {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "request body is too large",
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -89,7 +173,7 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 
 	userId := r.Context().Value("currentUserID").(int)
 
-	newComment, err := commentUsercase.CreateCommentUsecase(reqBodyComment, eventId, userId)
+	newComment, err := commentUsercase.CreateCommentUsecase(reqBodyComment, eventId, userId, images)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -130,7 +214,9 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 
 	userId := r.Context().Value("currentUserID").(int)
 
-	deleteComment, err := commentUsercase.DeleteCommentUsecase(commentId, eventId, userId)
+	reason := r.URL.Query().Get("reason")
+
+	deleteComment, err := commentUsercase.DeleteCommentUsecase(commentId, eventId, userId, reason)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -147,10 +233,211 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func EditComment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBodyComment *usecases.ReqBodyComment
+	r.Body = http.MaxBytesReader(w, r.Body, maxCommentBodySize)
+	err := json.NewDecoder(r.Body).Decode(&reqBodyComment)
+	if err != nil && err.Error() == "http: request body too large" {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "request body is too large",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	commentId, err := strconv.Atoi(params["comment_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	comment, err := commentUsercase.EditCommentUsecase(reqBodyComment, commentId, eventId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "edited comment successfully",
+		"comment":  comment,
+		"event_id": eventId,
+	})
+}
+
+func GetCommentHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	commentId, err := strconv.Atoi(params["comment_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	revisions, err := commentUsercase.GetCommentHistoryUsecase(commentId, eventId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "get comment history successfully",
+		"history":    revisions,
+		"comment_id": commentId,
+		"event_id":   eventId,
+	})
+}
+
+func ModerateComment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyModerateComment
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	commentId, err := strconv.Atoi(params["comment_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	comment, err := commentUsercase.ModerateCommentUsecase(&reqBody, commentId, eventId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "moderated comment successfully",
+		"action":   reqBody.Action,
+		"comment":  comment,
+		"event_id": eventId,
+	})
+}
+
+func GetCommentModerationLog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "page", SIZE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	logs, pageInfo, err := commentUsercase.GetModerationLogUsecase(eventId, userId, pagination)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get moderation log successfully",
+		"logs":        logs,
+		"event_id":    eventId,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
+	})
+}
+
 func init() {
 	db = database.ConnectDB()
 	commentRepo := repositories.NewCommentRepo(db)
 	userEventRepo := repositories.NewUserEventRepo(db)
 
-	commentUsercase = usecases.NewCommentUsecase(commentRepo, userEventRepo)
+	eventRepo := repositories.NewEventRepo(db)
+	eventRoleRepo := repositories.NewEventRoleRepo(db)
+
+	moderator := usecases.NewContentModeratorFromEnv(commentRepo, eventRepo, repositories.NewUserRepo(db))
+
+	commentUsercase = usecases.NewCommentUsecase(commentRepo, userEventRepo, eventRepo, eventRoleRepo, repositories.NewReactionRepo(db), repositories.NewNotificationRepo(db), repositories.NewUserRepo(db), repositories.NewModerationRepo(db), moderator, usecases.NewUploadUsecase(repositories.NewImageRepo(db)), repositories.NewUnitOfWork(db))
 }
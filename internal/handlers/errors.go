@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"together-backend/internal/repositories"
+)
+
+// listErrorStatus answers a bad filter, sort or cursor with 400 and any
+// other failure of a list with 500.
+func listErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrInvalidQuery) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", fmt.Sprintf("\"%d\"", version))
+}
+
+// ifMatchVersion reads the version the client last saw from the If-Match header.
+func ifMatchVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), "\"")
+	if value == "" {
+		return 0, fmt.Errorf("If-Match header is required")
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("If-Match header is invalid")
+	}
+	return version, nil
+}
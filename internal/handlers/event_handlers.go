@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"together-backend/internal/database"
@@ -39,6 +42,20 @@ func CreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the creator is whoever is signed in, never what the form claims
+	userId := r.Context().Value("currentUserID").(int)
+	reqBody.CreatedBy = uint64(userId)
+
+	if reqBody.TemplateId != 0 {
+		if err := eventTemplateUsecase.ApplyTemplateUsecase(userId, reqBody.TemplateId, reqBody); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+	}
+
 	files := r.MultipartForm.File["images"]
 	imagesSlice, err := uploadUsecase.EventImageUpload(files)
 	if err != nil {
@@ -67,42 +84,34 @@ func CreateEvent(w http.ResponseWriter, r *http.Request) {
 
 func GetEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var (
-		err    error
-		page   int = 1
-		userId int
-		search string
-		qType  string
-	)
 	queries := r.URL.Query()
-	if len(queries["page"]) > 0 {
-		page, err = strconv.Atoi(queries["page"][0])
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{
-				"message": "failed to parse query page",
-			})
-			return
-		}
-	}
-	if len(queries["search"]) > 0 {
-		search = queries["search"][0]
-	}
-	if len(queries["user_id"]) > 0 {
-		userId, err = strconv.Atoi(queries["user_id"][0])
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			json.NewEncoder(w).Encode(map[string]string{
-				"message": "failed to parse query page",
-			})
-			return
-		}
+	pagination, err := transfers.ParsePagination(queries, "page", SIZE_PER_PAGE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
 	}
-	if len(queries["type"]) > 0 {
-		qType = queries["type"][0]
+
+	query, err := transfers.ParseEventQuery(queries)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query: " + err.Error(),
+		})
+		return
 	}
+	query.ViewerId, _ = r.Context().Value("currentUserID").(int)
 
-	events, total, err := eventUsecase.GetEventsUsecase(page, SIZE_PER_PAGE, userId, search, qType)
+	events, pageInfo, total, err := eventUsecase.GetEventsUsecase(query, pagination)
+	if errors.Is(err, repositories.ErrInvalidQuery) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query: " + err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -113,10 +122,13 @@ func GetEvents(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "get events successfully",
-		"events":  events,
-		"total":   total,
-		"page":    page,
+		"message":     "get events successfully",
+		"events":      events,
+		"total":       total,
+		"page":        pagination.Page,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
 	})
 }
 
@@ -133,7 +145,9 @@ func GetEventDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	event, err := eventUsecase.GetEventDetailUsecase(eventId)
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	event, err := eventUsecase.GetEventDetailUsecase(eventId, viewerId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -142,6 +156,7 @@ func GetEventDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, event.EventDetail.Version)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "get events successfully",
@@ -192,6 +207,15 @@ func UpdateEvent(w http.ResponseWriter, r *http.Request) {
 
 	userId := r.Context().Value("currentUserID").(int)
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		w.WriteHeader(http.StatusPreconditionRequired)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
 	reqBody, err := transfers.ParseRequestUpdateEvent(r.MultipartForm)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -200,6 +224,7 @@ func UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	reqBody.Version = version
 
 	files := r.MultipartForm.File["images"]
 	imagesSlice, err := uploadUsecase.EventImageUpload(files)
@@ -211,7 +236,19 @@ func UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updatedEvent, err := eventUsecase.UpdateEventUsecase(userId, reqBody, imagesSlice)
+	updatedEvent, changes, err := eventUsecase.UpdateEventUsecase(userId, reqBody, imagesSlice)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		current, getErr := eventUsecase.GetEventDetailUsecase(int(reqBody.Id), userId)
+		if getErr == nil {
+			setETag(w, current.EventDetail.Version)
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": err.Error(),
+			"event":   current,
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -220,10 +257,12 @@ func UpdateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, updatedEvent.Version)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "update event successfully",
 		"event":   updatedEvent,
+		"changes": changes,
 	})
 }
 
@@ -242,7 +281,63 @@ func JoinEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	event, mess, err := eventUsecase.JoinEventUsecase(userId, eventId)
+	strict := false
+	if r.URL.Query().Get("strict") != "" {
+		strict, err = strconv.ParseBool(r.URL.Query().Get("strict"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "failed to parse query strict",
+			})
+			return
+		}
+	}
+
+	event, mess, warning, err := eventUsecase.JoinEventUsecase(userId, eventId, strict)
+	if err != nil {
+		var conflictErr *usecases.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"message":   err.Error(),
+				"conflicts": conflictErr.Conflicts,
+			})
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	res := map[string]interface{}{
+		"message": mess,
+		"event":   event,
+	}
+	if warning != nil {
+		res["warning"] = warning
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
+
+func GetEventTicket(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	ticket, err := eventUsecase.GetTicketUsecase(userId, eventId)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -253,17 +348,270 @@ func JoinEvent(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": mess,
+		"message": "get ticket successfully",
+		"ticket":  ticket,
+	})
+}
+
+type reqBodyCheckIn struct {
+	Payload string `json:"payload"`
+}
+
+func CheckIn(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody reqBodyCheckIn
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	userEvent, err := eventUsecase.CheckInUsecase(userId, eventId, reqBody.Payload)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":       "checked in successfully",
+		"event_id":      userEvent.EventId,
+		"user_id":       userEvent.UserId,
+		"checked_in_at": userEvent.CheckedInAt,
+	})
+}
+
+func ChangeEventStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyEventStatus
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	event, err := eventUsecase.ChangeEventStatusUsecase(userId, eventId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "change event status successfully",
 		"event":   event,
 	})
 }
 
+func DuplicateEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyDuplicateEvent
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	newEvent, err := eventUsecase.DuplicateEventUsecase(userId, eventId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "duplicate event successfully",
+		"event":   newEvent,
+	})
+}
+
+func GetEventHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "page", SIZE_PER_PAGE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	revisions, pageInfo, err := eventUsecase.GetEventHistoryUsecase(eventId, viewerId, pagination)
+	if err != nil {
+		w.WriteHeader(listErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get event history successfully",
+		"history":     revisions,
+		"event_id":    eventId,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
+	})
+}
+
+func GetEventGallery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "page", SIZE_PER_PAGE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	images, pageInfo, err := eventUsecase.GetEventGalleryUsecase(eventId, viewerId, pagination)
+	if err != nil {
+		w.WriteHeader(listErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get event gallery successfully",
+		"images":      images,
+		"event_id":    eventId,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
+	})
+}
+
+func ExportEventAttendees(w http.ResponseWriter, r *http.Request) {
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	export, err := eventUsecase.ExportAttendeesUsecase(userId, eventId, r.URL.Query().Get("format"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	w.WriteHeader(http.StatusOK)
+	// the status is already sent, so a failure can only cut the download short
+	if err := export.Write(w); err != nil {
+		log.Printf("failed to export attendees of event %d: %s", eventId, err)
+	}
+}
+
 func init() {
 	db = database.ConnectDB()
 	eventRepo := repositories.NewEventRepo(db)
 	userRepo := repositories.NewUserRepo(db)
 	imageRepo := repositories.NewImageRepo(db)
 	userEventRepo := repositories.NewUserEventRepo(db)
-	eventUsecase = usecases.NewEventUsecase(eventRepo, userRepo, imageRepo, userEventRepo)
+	notificationRepo := repositories.NewNotificationRepo(db)
+	eventRoleRepo := repositories.NewEventRoleRepo(db)
+	eventRevisionRepo := repositories.NewEventRevisionRepo(db)
+	eventUsecase = usecases.NewEventUsecase(eventRepo, eventRoleRepo, userRepo, imageRepo, userEventRepo, notificationRepo, eventRevisionRepo, repositories.NewEventReviewRepo(db), repositories.NewEventSessionRepo(db), repositories.NewReactionRepo(db), repositories.NewModerationRepo(db), usecases.NewContentModeratorFromEnv(repositories.NewCommentRepo(db), eventRepo, userRepo), repositories.NewUnitOfWork(db))
 	uploadUsecase = usecases.NewUploadUsecase(imageRepo)
 }
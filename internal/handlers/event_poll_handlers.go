@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
+	"together-backend/internal/usecases"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	eventPollUsecase usecases.EventPollUseCase
+)
+
+func GetEventPolls(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	polls, err := eventPollUsecase.GetPollsUsecase(eventId, viewerId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "get event polls successfully",
+		"polls":    polls,
+		"event_id": eventId,
+	})
+}
+
+func CreateEventPoll(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyPoll
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	poll, err := eventPollUsecase.CreatePollUsecase(userId, eventId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "created event poll successfully",
+		"poll":    poll,
+	})
+}
+
+func VoteEventPoll(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+	pollId, err := strconv.Atoi(params["poll_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyVote
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	poll, err := eventPollUsecase.VoteUsecase(userId, eventId, pollId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "voted successfully",
+		"poll":    poll,
+	})
+}
+
+func CloseEventPoll(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+	pollId, err := strconv.Atoi(params["poll_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	poll, err := eventPollUsecase.ClosePollUsecase(userId, eventId, pollId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "closed event poll successfully",
+		"poll":    poll,
+	})
+}
+
+func ApplyEventPoll(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+	pollId, err := strconv.Atoi(params["poll_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	// applying moves the event, so it takes the event version like an update
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		w.WriteHeader(http.StatusPreconditionRequired)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyApplyPoll
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+	reqBody.Version = version
+
+	event, err := eventPollUsecase.ApplyPollUsecase(userId, eventId, pollId, &reqBody)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	setETag(w, event.Version)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "applied event poll successfully",
+		"event":   event,
+	})
+}
+
+func DeleteEventPoll(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+	pollId, err := strconv.Atoi(params["poll_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	poll, err := eventPollUsecase.DeletePollUsecase(userId, eventId, pollId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "deleted event poll successfully",
+		"poll":    poll,
+	})
+}
+
+func init() {
+	db = database.ConnectDB()
+	eventRepo := repositories.NewEventRepo(db)
+	eventRoleRepo := repositories.NewEventRoleRepo(db)
+	eventPollRepo := repositories.NewEventPollRepo(db)
+	userEventRepo := repositories.NewUserEventRepo(db)
+	// eventUsecase is set up by event_handlers.go, which initializes first
+	eventPollUsecase = usecases.NewEventPollUsecase(eventRepo, eventRoleRepo, eventPollRepo, userEventRepo, eventUsecase, repositories.NewUnitOfWork(db))
+}
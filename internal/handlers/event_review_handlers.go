@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"together-backend/internal/transfers"
+	"together-backend/internal/usecases"
+
+	"github.com/gorilla/mux"
+)
+
+func GetEventReviews(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	pagination, err := transfers.ParsePagination(r.URL.Query(), "page", SIZE_PER_PAGE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	viewerId, _ := r.Context().Value("currentUserID").(int)
+
+	reviews, summary, pageInfo, err := eventUsecase.GetReviewsUsecase(eventId, viewerId, pagination)
+	if err != nil {
+		w.WriteHeader(listErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get event reviews successfully",
+		"reviews":     reviews,
+		"rating":      summary,
+		"event_id":    eventId,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
+	})
+}
+
+func CreateEventReview(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	var reqBody usecases.ReqBodyReview
+	err = json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	review, err := eventUsecase.CreateReviewUsecase(userId, eventId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "review event successfully",
+		"review":  review,
+	})
+}
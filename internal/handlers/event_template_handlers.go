@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
+	"together-backend/internal/usecases"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	eventTemplateUsecase usecases.EventTemplateUseCase
+)
+
+func GetEventTemplates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	templates, err := eventTemplateUsecase.GetTemplatesUsecase(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "get templates successfully",
+		"templates": templates,
+	})
+}
+
+func CreateEventTemplate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	var reqBody usecases.ReqBodyEventTemplate
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	template, err := eventTemplateUsecase.CreateTemplateUsecase(userId, &reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "create template successfully",
+		"template": template,
+	})
+}
+
+func DeleteEventTemplate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	templateId, err := strconv.Atoi(params["template_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	template, err := eventTemplateUsecase.DeleteTemplateUsecase(userId, templateId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "deleted template successfully",
+		"template": template,
+	})
+}
+
+func init() {
+	db = database.ConnectDB()
+	eventTemplateRepo := repositories.NewEventTemplateRepo(db)
+	eventRepo := repositories.NewEventRepo(db)
+	eventRoleRepo := repositories.NewEventRoleRepo(db)
+	eventTemplateUsecase = usecases.NewEventTemplateUsecase(eventTemplateRepo, eventRepo, eventRoleRepo)
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
+	"together-backend/internal/transfers"
+	"together-backend/internal/usecases"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	moderationUsecase usecases.ModerationUseCase
+)
+
+func ReportEvent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyReport
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.ReportEventUsecase(&reqBody, eventId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "reported event successfully",
+		"item_id":  item.Id,
+		"event_id": eventId,
+	})
+}
+
+func ReportComment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyReport
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	eventId, err := strconv.Atoi(params["event_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	commentId, err := strconv.Atoi(params["comment_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.ReportCommentUsecase(&reqBody, commentId, eventId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":    "reported comment successfully",
+		"item_id":    item.Id,
+		"comment_id": commentId,
+		"event_id":   eventId,
+	})
+}
+
+func ReportUser(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyReport
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	reportedId, err := strconv.Atoi(params["user_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.ReportUserUsecase(&reqBody, reportedId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "reported user successfully",
+		"item_id": item.Id,
+		"user_id": reportedId,
+	})
+}
+
+func GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	queries := r.URL.Query()
+
+	pagination, err := transfers.ParsePagination(queries, "page", SIZE_PER_PAGE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	filter, err := transfers.ParseModerationFilter(queries, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	items, pageInfo, err := moderationUsecase.GetQueueUsecase(userId, filter, pagination)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "get moderation queue successfully",
+		"items":       items,
+		"limit":       pageInfo.Limit,
+		"next_cursor": pageInfo.NextCursor,
+		"prev_cursor": pageInfo.PrevCursor,
+	})
+}
+
+func GetModerationItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(r)
+	itemId, err := strconv.Atoi(params["item_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.GetQueueItemUsecase(itemId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "get moderation item successfully",
+		"item":    item,
+	})
+}
+
+func AssignModerationItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyAssignItem
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	itemId, err := strconv.Atoi(params["item_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.AssignItemUsecase(&reqBody, itemId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "assigned moderation item successfully",
+		"item":    item,
+	})
+}
+
+func ResolveModerationItem(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var reqBody usecases.ReqBodyResolveItem
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse request body",
+		})
+		return
+	}
+
+	params := mux.Vars(r)
+	itemId, err := strconv.Atoi(params["item_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	item, err := moderationUsecase.ResolveItemUsecase(&reqBody, itemId, userId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "resolved moderation item successfully",
+		"item":    item,
+	})
+}
+
+func init() {
+	db = database.ConnectDB()
+
+	autoHideReports, err := strconv.Atoi(os.Getenv("MODERATION_AUTO_HIDE_REPORTS"))
+	if err != nil {
+		autoHideReports = usecases.DefaultAutoHideReports
+	}
+
+	moderationUsecase = usecases.NewModerationUsecase(repositories.NewModerationRepo(db), repositories.NewEventRepo(db), repositories.NewCommentRepo(db), repositories.NewUserRepo(db), repositories.NewUnitOfWork(db), autoHideReports)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
+	"together-backend/internal/transfers"
+	"together-backend/internal/usecases"
+)
+
+var (
+	notificationUsecase usecases.NotificationUseCase
+)
+
+func GetNotifications(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	queries := r.URL.Query()
+	pagination, err := transfers.ParsePagination(queries, "page", SIZE)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query page",
+		})
+		return
+	}
+
+	notifications, pageInfo, unread, err := notificationUsecase.GetNotificationsUsecase(userId, queries.Get("unread") == "true", pagination)
+	if err != nil {
+		w.WriteHeader(listErrorStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":       "get notifications successfully",
+		"notifications": notifications,
+		"unread":        unread,
+		"limit":         pageInfo.Limit,
+		"next_cursor":   pageInfo.NextCursor,
+		"prev_cursor":   pageInfo.PrevCursor,
+	})
+}
+
+func MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userId := r.Context().Value("currentUserID").(int)
+
+	var reqBody usecases.ReqBodyMarkRead
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "failed to parse request body",
+			})
+			return
+		}
+	}
+
+	updated, err := notificationUsecase.MarkReadUsecase(userId, reqBody.Ids)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "marked notifications as read",
+		"updated": updated,
+	})
+}
+
+func init() {
+	db = database.ConnectDB()
+	notificationRepo := repositories.NewNotificationRepo(db)
+	notificationUsecase = usecases.NewNotificationUsecase(notificationRepo)
+}
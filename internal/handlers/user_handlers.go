@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"together-backend/internal/database"
@@ -38,6 +39,7 @@ func GetUserDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, user.Version)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "get user detail successfully",
@@ -76,6 +78,15 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		w.WriteHeader(http.StatusPreconditionRequired)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
 	reqBody, err := transfers.ParseRequestUpdateProfile(r.MultipartForm)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -98,7 +109,19 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	updatedUser, err := userUsercase.UpdateProfilelUsecase(userId, reqBody.Name, reqBody.Address, avatarUrl)
+	updatedUser, err := userUsercase.UpdateProfilelUsecase(userId, reqBody.Name, reqBody.Address, avatarUrl, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		current, getErr := userUsercase.GetUserDetailUsecase(userId)
+		if getErr == nil {
+			setETag(w, current.Version)
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": err.Error(),
+			"user":    current,
+		})
+		return
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{
@@ -107,6 +130,7 @@ func UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, updatedUser.Version)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"message": "update profile successfully",
@@ -169,6 +193,47 @@ func ChangePassword(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func GetUserSchedule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	viewerId := r.Context().Value("currentUserID").(int)
+
+	params := mux.Vars(r)
+	userId, err := strconv.Atoi(params["user_id"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse params",
+		})
+		return
+	}
+
+	from, to, err := transfers.ParseTimeRange(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "failed to parse query from/to",
+		})
+		return
+	}
+
+	schedule, err := eventUsecase.GetUserScheduleUsecase(userId, viewerId, from, to)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":  "get user schedule successfully",
+		"user_id":  userId,
+		"schedule": schedule,
+	})
+}
+
 func init() {
 	db = database.ConnectDB()
 	userRepo := repositories.NewUserRepo(db)
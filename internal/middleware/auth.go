@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"time"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
 	"together-backend/pkg"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
+var userRepo repositories.UserRepo
+
 type Claims struct {
 	UserId int    `json:"user_id"`
 	Email  string `json:"email"`
@@ -60,7 +65,61 @@ func Auth(handler http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		// tokens outlive a suspension, so it is checked on every change;
+		// suspended users keep read access
+		if r.Method != http.MethodGet {
+			user, err := userRepo.GetUserById(int64(claims.UserId))
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"message": "unauthorized",
+				})
+				return
+			}
+			if user.Suspended(time.Now()) {
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"message": "your account is suspended",
+				})
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), "currentUserID", claims.UserId)
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// OptionalAuth sets currentUserID when a valid token is sent and lets
+// anonymous requests through otherwise.
+func OptionalAuth(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header["Authorization"] == nil {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		token := pkg.BearerAuthHeader(r.Header["Authorization"][0])
+		if token == "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var jwtKey = []byte(os.Getenv("SECRET_KEY"))
+		var claims = &Claims{}
+		tokenParse, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+			return jwtKey, nil
+		})
+		if err != nil || !tokenParse.Valid {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), "currentUserID", claims.UserId)
 		handler.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
+
+func init() {
+	userRepo = repositories.NewUserRepo(database.ConnectDB())
+}
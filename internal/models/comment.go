@@ -2,6 +2,7 @@ package models
 
 import (
 	"time"
+	"together-backend/pkg"
 
 	"gorm.io/gorm"
 )
@@ -10,10 +11,94 @@ type Comment struct {
 	Id      uint `json:"id" gorm:"primaryKey"`
 	EventId uint `json:"event_id"`
 	// Event     Event          `gorm:"references:Id"`
-	UserId    uint           `json:"user_id"`
-	User      User           `json:"user" gorm:"references:Id"`
-	Content   string         `json:"content"`
+	UserId      uint             `json:"user_id"`
+	User        User             `json:"user" gorm:"references:Id"`
+	ParentId    *uint            `json:"parent_id"`
+	Content     string           `json:"content"`
+	ContentHTML string           `json:"content_html"`
+	Mentions    []CommentMention `json:"mentions" gorm:"foreignKey:CommentId"`
+	Images      []CommentImage   `json:"images" gorm:"foreignKey:CommentId"`
+	EditedAt    *time.Time       `json:"edited_at"`
+	HiddenAt    *time.Time       `json:"hidden_at,omitempty"`
+	PinnedAt    *time.Time       `json:"pinned_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
+
+	ReplyCount int64           `json:"reply_count" gorm:"->;-:migration"`
+	Replies    []Comment       `json:"replies,omitempty" gorm:"-"`
+	Reactions  []ReactionCount `json:"reactions" gorm:"-"`
+}
+
+// AfterFind renders content saved before content_html was stored.
+func (c *Comment) AfterFind(tx *gorm.DB) error {
+	if c.ContentHTML == "" && c.Content != "" {
+		c.ContentHTML = pkg.RenderMarkdown(c.Content)
+	}
+	return nil
+}
+
+// MaxCommentLength is how many characters of Markdown a comment may hold.
+const MaxCommentLength = 5000
+
+// MaxCommentImages is how many images a comment may carry.
+const MaxCommentImages = 4
+
+// CommentImage is an image attached to a comment. EventId is copied from
+// the comment so an event's gallery reads one table.
+type CommentImage struct {
+	Id        uint           `json:"id" gorm:"primaryKey"`
+	CommentId uint           `json:"comment_id"`
+	EventId   uint           `json:"event_id"`
+	ImageUrl  string         `json:"image_url"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+const (
+	CommentActionHide   = "hide"
+	CommentActionUnhide = "unhide"
+	CommentActionPin    = "pin"
+	CommentActionUnpin  = "unpin"
+	CommentActionDelete = "delete"
+)
+
+// MaxPinnedComments is how many comments an event can pin at once.
+const MaxPinnedComments = 3
+
+// CommentModerationLog records a moderation action taken on a comment.
+// Content keeps what the comment said, so deletions stay auditable.
+type CommentModerationLog struct {
+	Id        uint      `json:"id" gorm:"primaryKey"`
+	EventId   uint      `json:"event_id"`
+	CommentId uint      `json:"comment_id"`
+	ActorId   uint      `json:"actor_id"`
+	Actor     User      `json:"actor" gorm:"foreignKey:ActorId"`
+	Action    string    `json:"action"`
+	Reason    string    `json:"reason"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentRevision keeps the content a comment had before an edit.
+type CommentRevision struct {
+	Id        uint      `json:"id" gorm:"primaryKey"`
+	CommentId uint      `json:"comment_id"`
+	Content   string    `json:"content"`
+	EditedBy  uint      `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommentMention is an @mention in a comment resolved to an attendee.
+// Offset and Length count characters of the comment content.
+type CommentMention struct {
+	Id        uint      `json:"-" gorm:"primaryKey"`
+	CommentId uint      `json:"-"`
+	UserId    uint      `json:"user_id"`
+	Offset    int       `json:"offset" gorm:"column:start_offset"`
+	Length    int       `json:"length"`
+	CreatedAt time.Time `json:"-"`
+
+	Name string `json:"name" gorm:"->;-:migration"`
+}
@@ -2,23 +2,65 @@ package models
 
 import (
 	"time"
+	"together-backend/pkg"
 
 	"gorm.io/gorm"
 )
 
+const (
+	EventStatusDraft     = "draft"
+	EventStatusPublished = "published"
+	EventStatusPostponed = "postponed"
+	EventStatusCancelled = "cancelled"
+	EventStatusCompleted = "completed"
+)
+
+// MaxEventContentLength is how many characters of Markdown an event's
+// content may hold.
+const MaxEventContentLength = 20000
+
+// EventStatusTransitions lists the statuses an event may move to from each status.
+var EventStatusTransitions = map[string][]string{
+	EventStatusDraft:     {EventStatusPublished, EventStatusCancelled},
+	EventStatusPublished: {EventStatusPostponed, EventStatusCancelled, EventStatusCompleted},
+	EventStatusPostponed: {EventStatusPublished, EventStatusCancelled},
+	EventStatusCancelled: {},
+	EventStatusCompleted: {},
+}
+
 type Event struct {
-	Id             uint           `json:"id" gorm:"primaryKey"`
-	Title          string         `json:"title"`
-	Content        string         `json:"content"`
-	CreatedBy      uint64         `json:"created_by"`
-	StartTime      time.Time      `json:"start_time"`
-	EndTime        time.Time      `json:"end_time"`
-	Location       int            `json:"location"`
-	DetailLocation string         `json:"detail_location"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
-	EventImages    []EventImage   `json:"event_images" gorm:"foreignKey:EventId"`
-	Comments       []Comment      `json:"comments" gorm:"foreignKey:EventId"`
-	Users          []User         `json:"users" gorm:"many2many:user_events;"`
+	Id              uint           `json:"id" gorm:"primaryKey"`
+	Title           string         `json:"title"`
+	Content         string         `json:"content"`
+	ContentHTML     string         `json:"content_html"`
+	CreatedBy       uint64         `json:"created_by"`
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         time.Time      `json:"end_time"`
+	Location        int            `json:"location"`
+	DetailLocation  string         `json:"detail_location"`
+	Status          string         `json:"status" gorm:"default:published"`
+	StatusReason    string         `json:"status_reason"`
+	StatusChangedAt *time.Time     `json:"status_changed_at"`
+	PublishAt       *time.Time     `json:"publish_at"`
+	HiddenAt        *time.Time     `json:"hidden_at,omitempty"`
+	Version         int            `json:"version" gorm:"default:1"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	EventImages     []EventImage   `json:"event_images" gorm:"foreignKey:EventId"`
+	Comments        []Comment      `json:"comments" gorm:"foreignKey:EventId"`
+	Users           []User         `json:"users" gorm:"many2many:user_events;"`
+
+	SearchRank       float64 `json:"search_rank,omitempty" gorm:"->;-:migration"`
+	Popularity       int64   `json:"-" gorm:"->;-:migration"`
+	TitleHighlight   string  `json:"title_highlight,omitempty" gorm:"->;-:migration"`
+	ContentHighlight string  `json:"content_highlight,omitempty" gorm:"->;-:migration"`
+}
+
+// AfterFind renders content saved before content_html was stored.
+func (e *Event) AfterFind(tx *gorm.DB) error {
+	if e.ContentHTML == "" && e.Content != "" {
+		e.ContentHTML = pkg.RenderMarkdown(e.Content)
+	}
+	return nil
 }
@@ -14,3 +14,21 @@ type EventImage struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+const (
+	GalleryImageSourceEvent   = "event"
+	GalleryImageSourceComment = "comment"
+)
+
+// GalleryImage is an image of an event's gallery, either one of the event's
+// own images or one attached to a comment. Id is the image's id within its
+// source.
+type GalleryImage struct {
+	Id        uint      `json:"id"`
+	Source    string    `json:"source"`
+	ImageUrl  string    `json:"image_url"`
+	CommentId *uint     `json:"comment_id,omitempty"`
+	UserId    uint64    `json:"user_id"`
+	User      User      `json:"user" gorm:"foreignKey:UserId"`
+	CreatedAt time.Time `json:"created_at"`
+}
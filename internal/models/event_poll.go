@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PollTypeChoice = "choice"
+	PollTypeDate   = "date"
+)
+
+type EventPoll struct {
+	Id             uint           `json:"id" gorm:"primaryKey"`
+	EventId        uint           `json:"event_id"`
+	CreatedBy      uint           `json:"created_by"`
+	Question       string         `json:"question"`
+	Type           string         `json:"type" gorm:"default:choice"`
+	MultipleChoice bool           `json:"multiple_choice"`
+	Anonymous      bool           `json:"anonymous"`
+	ClosesAt       *time.Time     `json:"closes_at"`
+	AppliedAt      *time.Time     `json:"applied_at"`
+	Options        []PollOption   `json:"options" gorm:"foreignKey:PollId"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// Closed reports whether voting has ended at now.
+func (poll *EventPoll) Closed(now time.Time) bool {
+	return poll.ClosesAt != nil && !now.Before(*poll.ClosesAt)
+}
+
+// PollOption is one answer of a poll; options of a date poll carry the
+// time window they propose for the event.
+type PollOption struct {
+	Id        uint       `json:"id" gorm:"primaryKey"`
+	PollId    uint       `json:"poll_id"`
+	Position  int        `json:"position"`
+	Label     string     `json:"label"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+}
+
+type PollVote struct {
+	PollId    uint      `json:"poll_id"`
+	OptionId  uint      `json:"option_id" gorm:"primaryKey"`
+	UserId    uint      `json:"user_id" gorm:"primaryKey"`
+	User      User      `json:"user" gorm:"foreignKey:UserId"`
+	CreatedAt time.Time `json:"created_at"`
+}
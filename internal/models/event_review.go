@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+)
+
+type EventReview struct {
+	Id        uint      `json:"id" gorm:"primaryKey"`
+	EventId   uint      `json:"event_id"`
+	UserId    uint      `json:"user_id"`
+	User      User      `json:"user" gorm:"foreignKey:UserId"`
+	Rating    int       `json:"rating"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
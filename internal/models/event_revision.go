@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// FieldChanges is stored as a JSON column.
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (c *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into FieldChanges", value)
+}
+
+type EventRevision struct {
+	Id        uint         `json:"id" gorm:"primaryKey"`
+	EventId   uint         `json:"event_id"`
+	Version   int          `json:"version"`
+	ChangedBy uint         `json:"changed_by"`
+	User      User         `json:"user" gorm:"foreignKey:ChangedBy;references:Id"`
+	Changes   FieldChanges `json:"changes" gorm:"type:jsonb"`
+	CreatedAt time.Time    `json:"created_at"`
+}
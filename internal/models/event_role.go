@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	EventRoleOwner       = "owner"
+	EventRoleCoOrganizer = "co_organizer"
+	EventRoleModerator   = "moderator"
+)
+
+// EventRoleRanks orders roles by privilege, a higher rank can do everything a lower one can.
+var EventRoleRanks = map[string]int{
+	EventRoleModerator:   1,
+	EventRoleCoOrganizer: 2,
+	EventRoleOwner:       3,
+}
+
+// EventRole is a staff member of an event. The owner is the event's
+// CreatedBy and has no row of its own.
+type EventRole struct {
+	EventId   uint      `json:"event_id" gorm:"primaryKey"`
+	UserId    uint      `json:"user_id" gorm:"primaryKey"`
+	User      User      `json:"user" gorm:"references:Id"`
+	Role      string    `json:"role"`
+	InvitedBy uint      `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
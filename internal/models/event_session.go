@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventSession is a talk or slot inside an event. A zero Capacity means
+// the session is limited only by the event itself.
+type EventSession struct {
+	Id        uint           `json:"id" gorm:"primaryKey"`
+	EventId   uint           `json:"event_id"`
+	Title     string         `json:"title"`
+	Speaker   string         `json:"speaker"`
+	Room      string         `json:"room"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	Capacity  int            `json:"capacity"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Signups  int64 `json:"signups" gorm:"->;-:migration"`
+	SignedUp bool  `json:"signed_up" gorm:"->;-:migration"`
+}
+
+type SessionSignup struct {
+	SessionId uint      `json:"session_id" gorm:"primaryKey"`
+	UserId    uint      `json:"user_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+}
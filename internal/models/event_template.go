@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventTemplate struct {
+	Id             uint           `json:"id" gorm:"primaryKey"`
+	UserId         uint           `json:"user_id"`
+	Name           string         `json:"name"`
+	Title          string         `json:"title"`
+	Content        string         `json:"content"`
+	Duration       int            `json:"duration"` // minutes
+	Location       int            `json:"location"`
+	DetailLocation string         `json:"detail_location"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
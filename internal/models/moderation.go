@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Moderation actions, from the most to the least lenient. A pipeline of
+// filters settles on the strictest action any of them asks for.
+const (
+	ModerationAllow  = "allow"
+	ModerationMask   = "mask"
+	ModerationQueue  = "queue"
+	ModerationReject = "reject"
+)
+
+var ModerationActionRanks = map[string]int{
+	ModerationAllow:  0,
+	ModerationMask:   1,
+	ModerationQueue:  2,
+	ModerationReject: 3,
+}
+
+const (
+	ModerationTargetEvent   = "event"
+	ModerationTargetComment = "comment"
+	ModerationTargetUser    = "user"
+)
+
+const (
+	ModerationSourceFilter = "filter"
+	ModerationSourceReport = "report"
+)
+
+const (
+	ModerationStatusPending   = "pending"
+	ModerationStatusDismissed = "dismissed"
+	ModerationStatusActioned  = "actioned"
+)
+
+// Resolutions a moderator can give an item.
+const (
+	ModerationResolutionDismiss = "dismiss"
+	ModerationResolutionHide    = "hide"
+	ModerationResolutionSuspend = "suspend"
+)
+
+// ReportCategories are the reasons users can pick when reporting.
+var ReportCategories = []string{"spam", "harassment", "hate_speech", "violence", "sexual_content", "misinformation", "impersonation", "other"}
+
+func IsReportCategory(category string) bool {
+	for _, c := range ReportCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ModerationItem is content waiting for a moderator, raised by the content
+// filter or by user reports. TargetHidden tells whether the item itself hid
+// its target, so dismissing it can bring the target back.
+type ModerationItem struct {
+	Id           uint               `json:"id" gorm:"primaryKey"`
+	TargetType   string             `json:"target_type"`
+	TargetId     uint               `json:"target_id"`
+	EventId      *uint              `json:"event_id"`
+	AuthorId     uint               `json:"author_id"`
+	Source       string             `json:"source"`
+	Reason       string             `json:"reason"`
+	Status       string             `json:"status" gorm:"default:pending"`
+	TargetHidden bool               `json:"target_hidden"`
+	AssigneeId   *uint              `json:"assignee_id"`
+	Resolution   string             `json:"resolution"`
+	ResolvedBy   *uint              `json:"resolved_by"`
+	Note         string             `json:"note"`
+	CreatedAt    time.Time          `json:"created_at"`
+	ResolvedAt   *time.Time         `json:"resolved_at"`
+	Reports      []ModerationReport `json:"reports,omitempty" gorm:"foreignKey:ItemId"`
+
+	ReportCount int64 `json:"report_count" gorm:"->;-:migration"`
+}
+
+// ModerationReport is one user's report of an item. A user reports an open
+// item at most once.
+type ModerationReport struct {
+	Id         uint      `json:"id" gorm:"primaryKey"`
+	ItemId     uint      `json:"item_id"`
+	ReporterId uint      `json:"reporter_id"`
+	Reporter   User      `json:"reporter" gorm:"foreignKey:ReporterId"`
+	Category   string    `json:"category"`
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	NotificationEventStatus  = "event_status"
+	NotificationEventStaff   = "event_staff"
+	NotificationEventUpdated = "event_updated"
+	NotificationMention      = "comment_mention"
+)
+
+type Notification struct {
+	Id        uint       `json:"id" gorm:"primaryKey"`
+	UserId    uint       `json:"user_id"`
+	EventId   uint       `json:"event_id"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
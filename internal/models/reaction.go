@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	ReactionTargetEvent   = "event"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionEmojis is the fixed set of reactions, in display order.
+var ReactionEmojis = []string{"👍", "❤️", "😂", "😮", "😢", "🎉"}
+
+type Reaction struct {
+	Id         uint      `json:"id" gorm:"primaryKey"`
+	TargetType string    `json:"target_type"`
+	TargetId   uint      `json:"target_id"`
+	UserId     uint      `json:"user_id"`
+	Emoji      string    `json:"emoji"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReactionCount aggregates one emoji on one target for a viewer.
+type ReactionCount struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+func IsReactionEmoji(emoji string) bool {
+	for _, e := range ReactionEmojis {
+		if e == emoji {
+			return true
+		}
+	}
+	return false
+}
@@ -6,16 +6,32 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	UserRoleMember = "member"
+	// UserRoleModerator can moderate content on every event.
+	UserRoleModerator = "moderator"
+)
+
 type User struct {
-	Id        uint           `json:"id"`
-	Name      string         `json:"name"`
-	Email     string         `json:"email" gorm:"unique"`
-	Password  []byte         `json:"-"`
-	Avatar    string         `json:"avatar"`
-	Address   int            `json:"address"`
-	Events    []Event        `json:"events" gorm:"many2many:user_events;"`
-	Comments  []Comment      `json:"comments" gorm:"foreignKey:UserId"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	Id       uint   `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email" gorm:"unique"`
+	Password []byte `json:"-"`
+	Avatar   string `json:"avatar"`
+	Address  int    `json:"address"`
+	Role     string `json:"role" gorm:"default:member"`
+	// SuspendedAt is set while the user is suspended, SuspendedUntil is nil
+	// for suspensions without an end.
+	SuspendedAt    *time.Time     `json:"suspended_at,omitempty"`
+	SuspendedUntil *time.Time     `json:"suspended_until,omitempty"`
+	Version        int            `json:"version" gorm:"default:1"`
+	Events         []Event        `json:"events" gorm:"many2many:user_events;"`
+	Comments       []Comment      `json:"comments" gorm:"foreignKey:UserId"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (u *User) Suspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
 }
@@ -1,6 +1,10 @@
 package models
 
+import "time"
+
 type UserEvent struct {
-	UserId  uint `gorm:"primaryKey" column:"user_id"`
-	EventId uint `gorm:"primaryKey" column:"event_id"`
+	UserId      uint       `gorm:"primaryKey" column:"user_id"`
+	EventId     uint       `gorm:"primaryKey" column:"event_id"`
+	JoinedAt    *time.Time `json:"joined_at"`
+	CheckedInAt *time.Time `json:"checked_in_at"`
 }
@@ -1,18 +1,31 @@
 package repositories
 
 import (
+	"strconv"
+	"time"
 	"together-backend/internal/models"
+	"together-backend/pkg"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type CommentRepo interface {
-	CreateComment(userId, eventId int, content string) (*models.Comment, error)
-	GetCommentsByEventId(eventId, size, page int) ([]models.Comment, error)
+	CreateComment(userId, eventId int, parentId *uint, content string, mentions []models.CommentMention, imageUrls []string) (*models.Comment, error)
+	GetCommentsByEventId(eventId int, pagination *Pagination) ([]models.Comment, *PageInfo, error)
+	GetFirstReplies(parentIds []uint, perParent int) ([]models.Comment, error)
+	GetReplies(parentId int, pagination *Pagination) ([]models.Comment, *PageInfo, error)
 	CountCommentsByEventId(eventId int) (int64, error)
-	DeleteComment(comment *models.Comment) (*models.Comment, error)
+	DeleteComment(comment *models.Comment, log *models.CommentModerationLog) (*models.Comment, []string, error)
 	GetComment(commentId, userId, eventId int) (*models.Comment, error)
+	GetEventComment(commentId, eventId int) (*models.Comment, error)
+	UpdateComment(comment *models.Comment, content string, mentions []models.CommentMention, editedBy int, editedAt time.Time) (*models.Comment, error)
+	GetCommentRevisions(commentId int) ([]models.CommentRevision, error)
+	GetPinnedComments(eventId int) ([]models.Comment, error)
+	CountPinnedComments(eventId int) (int64, error)
+	ModerateComment(comment *models.Comment, updates map[string]interface{}, log *models.CommentModerationLog) (*models.Comment, error)
+	GetModerationLogs(eventId int, pagination *Pagination) ([]models.CommentModerationLog, *PageInfo, error)
+	CountUserCommentsSince(userId int, since time.Time) (int64, error)
 }
 
 type commentDB struct {
@@ -25,11 +38,20 @@ func NewCommentRepo(db *gorm.DB) CommentRepo {
 	}
 }
 
-func (commentDB *commentDB) CreateComment(userId, eventId int, content string) (*models.Comment, error) {
+func (commentDB *commentDB) CreateComment(userId, eventId int, parentId *uint, content string, mentions []models.CommentMention, imageUrls []string) (*models.Comment, error) {
 	comment := models.Comment{
-		EventId: uint(eventId),
-		UserId:  uint(userId),
-		Content: content,
+		EventId:     uint(eventId),
+		UserId:      uint(userId),
+		ParentId:    parentId,
+		Content:     content,
+		ContentHTML: pkg.RenderMarkdown(content),
+		Mentions:    mentions,
+	}
+	for _, imageUrl := range imageUrls {
+		comment.Images = append(comment.Images, models.CommentImage{
+			EventId:  uint(eventId),
+			ImageUrl: imageUrl,
+		})
 	}
 	if err := commentDB.db.Create(&comment).Error; err != nil {
 		return nil, err
@@ -39,18 +61,80 @@ func (commentDB *commentDB) CreateComment(userId, eventId int, content string) (
 	return &comment, nil
 }
 
-func (commentDB *commentDB) GetCommentsByEventId(eventId, size, page int) ([]models.Comment, error) {
+var commentKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "comments.created_at", Where: "comments.created_at", Kind: keysetTime, Desc: true},
+	{Name: "id", Order: "comments.id", Where: "comments.id", Kind: keysetInt, Desc: true},
+}}
+
+// replies read oldest first, so a thread reads like a conversation
+var replyKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "comments.created_at", Where: "comments.created_at", Kind: keysetTime},
+	{Name: "id", Order: "comments.id", Where: "comments.id", Kind: keysetInt},
+}}
+
+// preloadAttachments loads the images and mentions of a page of comments,
+// with the names of the mentioned users.
+func preloadAttachments(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("comment_images.id")
+	}).Preload("Mentions", func(db *gorm.DB) *gorm.DB {
+		return db.Select("comment_mentions.*, users.name").
+			Joins("JOIN users ON users.id = comment_mentions.user_id").
+			Order("comment_mentions.start_offset")
+	})
+}
+
+const replyCountSelect = "comments.*, (SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id AND replies.hidden_at IS NULL AND replies.deleted_at IS NULL) AS reply_count"
+
+func commentKeysetValues(comment *models.Comment) []string {
+	return []string{comment.CreatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(comment.Id), 10)}
+}
+
+func (commentDB *commentDB) GetCommentsByEventId(eventId int, pagination *Pagination) ([]models.Comment, *PageInfo, error) {
 	var comments []models.Comment
-	err := commentDB.db.Preload("User").
-		Where("event_id = ?", eventId).
-		Limit(size).Offset((page - 1) * size).
-		Order("created_at desc").
-		Find(&comments).Error
+	pageInfo, err := commentKeyset.fetch(preloadAttachments(commentDB.db.Preload("User")).Select(replyCountSelect).Where("event_id = ? AND parent_id IS NULL AND hidden_at IS NULL AND pinned_at IS NULL", eventId), pagination, &comments, func(i int) []string {
+		return commentKeysetValues(&comments[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return comments, pageInfo, nil
+}
+
+// GetFirstReplies loads the oldest perParent replies of every thread in
+// one query.
+func (commentDB *commentDB) GetFirstReplies(parentIds []uint, perParent int) ([]models.Comment, error) {
+	var replies []models.Comment
+	if len(parentIds) == 0 {
+		return replies, nil
+	}
+
+	ranked := commentDB.db.Model(&models.Comment{}).
+		Select("comments.*, ROW_NUMBER() OVER (PARTITION BY comments.parent_id ORDER BY comments.created_at, comments.id) AS reply_rank").
+		Where("comments.parent_id IN ? AND comments.hidden_at IS NULL", parentIds)
+	err := preloadAttachments(commentDB.db.Preload("User")).
+		Table("(?) AS comments", ranked).
+		Where("reply_rank <= ?", perParent).
+		Order("comments.created_at, comments.id").
+		Find(&replies).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return comments, nil
+	return replies, nil
+}
+
+func (commentDB *commentDB) GetReplies(parentId int, pagination *Pagination) ([]models.Comment, *PageInfo, error) {
+	var replies []models.Comment
+	pageInfo, err := replyKeyset.fetch(preloadAttachments(commentDB.db.Preload("User")).Where("parent_id = ? AND hidden_at IS NULL", parentId), pagination, &replies, func(i int) []string {
+		return commentKeysetValues(&replies[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return replies, pageInfo, nil
 }
 
 func (commentDB *commentDB) CountCommentsByEventId(eventId int) (int64, error) {
@@ -58,7 +142,7 @@ func (commentDB *commentDB) CountCommentsByEventId(eventId int) (int64, error) {
 		total    int64
 		comments models.Comment
 	)
-	err := commentDB.db.Where("event_id = ?", eventId).Find(&comments).Count(&total).Error
+	err := commentDB.db.Where("event_id = ? AND parent_id IS NULL AND hidden_at IS NULL", eventId).Find(&comments).Count(&total).Error
 	if err != nil {
 		return int64(0), err
 	}
@@ -68,7 +152,7 @@ func (commentDB *commentDB) CountCommentsByEventId(eventId int) (int64, error) {
 
 func (commentDB *commentDB) GetComment(commentId, userId, eventId int) (*models.Comment, error) {
 	var comment models.Comment
-	err := commentDB.db.Preload("User").
+	err := preloadAttachments(commentDB.db.Preload("User")).
 		Where("id = ? AND user_id = ? AND event_id = ?", commentId, userId, eventId).
 		First(&comment).Error
 	if err != nil {
@@ -78,11 +162,190 @@ func (commentDB *commentDB) GetComment(commentId, userId, eventId int) (*models.
 	return &comment, nil
 }
 
-func (commentDB *commentDB) DeleteComment(comment *models.Comment) (*models.Comment, error) {
-	if err := commentDB.db.Clauses(clause.Returning{}).
-		Unscoped().Delete(&comment).Error; err != nil {
+func (commentDB *commentDB) GetEventComment(commentId, eventId int) (*models.Comment, error) {
+	var comment models.Comment
+	err := preloadAttachments(commentDB.db.Preload("User")).
+		Where("id = ? AND event_id = ?", commentId, eventId).
+		First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment removes the comment and its thread. A moderation log, when
+// given, is written in the same transaction. It returns the URLs of the
+// thread's images, whose files the caller removes once this commits.
+func (commentDB *commentDB) DeleteComment(comment *models.Comment, log *models.CommentModerationLog) (*models.Comment, []string, error) {
+	var images []models.CommentImage
+	err := commentDB.db.Transaction(func(tx *gorm.DB) error {
+		if log != nil {
+			if err := tx.Create(log).Error; err != nil {
+				return err
+			}
+		}
+		thread := tx.Model(&models.Comment{}).Unscoped().Select("id").Where("id = ? OR parent_id = ?", comment.Id, comment.Id)
+		err := tx.Where("comment_id IN (?)", thread).
+			Delete(&models.CommentRevision{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("comment_id IN (?)", thread).
+			Delete(&models.CommentMention{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Clauses(clause.Returning{}).Unscoped().Where("comment_id IN (?)", thread).
+			Delete(&images).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("target_type = ? AND target_id IN (?)", models.ReactionTargetComment, thread).
+			Delete(&models.Reaction{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Where("parent_id = ?", comment.Id).
+			Delete(&models.Comment{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Clauses(clause.Returning{}).Unscoped().Delete(&comment).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var imageUrls []string
+	for _, image := range images {
+		imageUrls = append(imageUrls, image.ImageUrl)
+	}
+	return comment, imageUrls, nil
+}
+
+// UpdateComment stores the current content as a revision, then replaces it
+// along with its mentions.
+func (commentDB *commentDB) UpdateComment(comment *models.Comment, content string, mentions []models.CommentMention, editedBy int, editedAt time.Time) (*models.Comment, error) {
+	err := commentDB.db.Transaction(func(tx *gorm.DB) error {
+		revision := models.CommentRevision{
+			CommentId: comment.Id,
+			Content:   comment.Content,
+			EditedBy:  uint(editedBy),
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.Id).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+		for i := range mentions {
+			mentions[i].CommentId = comment.Id
+		}
+		if len(mentions) > 0 {
+			if err := tx.Create(&mentions).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(comment).Updates(map[string]interface{}{
+			"content":      content,
+			"content_html": pkg.RenderMarkdown(content),
+			"edited_at":    editedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	comment.Mentions = mentions
+
+	return comment, nil
+}
+
+func (commentDB *commentDB) GetCommentRevisions(commentId int) ([]models.CommentRevision, error) {
+	var revisions []models.CommentRevision
+	err := commentDB.db.Where("comment_id = ?", commentId).
+		Order("created_at desc, id desc").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// GetPinnedComments returns the pinned comments of the event, most recently
+// pinned first.
+func (commentDB *commentDB) GetPinnedComments(eventId int) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := preloadAttachments(commentDB.db.Preload("User")).
+		Select(replyCountSelect).
+		Where("event_id = ? AND parent_id IS NULL AND hidden_at IS NULL AND pinned_at IS NOT NULL", eventId).
+		Order("pinned_at desc, id desc").
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (commentDB *commentDB) CountPinnedComments(eventId int) (int64, error) {
+	var total int64
+	err := commentDB.db.Model(&models.Comment{}).
+		Where("event_id = ? AND parent_id IS NULL AND pinned_at IS NOT NULL", eventId).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return total, nil
+}
+
+// ModerateComment applies a moderation change and records it in one
+// transaction.
+func (commentDB *commentDB) ModerateComment(comment *models.Comment, updates map[string]interface{}, log *models.CommentModerationLog) (*models.Comment, error) {
+	err := commentDB.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(comment).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return comment, nil
 }
+
+var moderationLogKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "comment_moderation_logs.created_at", Where: "comment_moderation_logs.created_at", Kind: keysetTime, Desc: true},
+	{Name: "id", Order: "comment_moderation_logs.id", Where: "comment_moderation_logs.id", Kind: keysetInt, Desc: true},
+}}
+
+func moderationLogKeysetValues(log *models.CommentModerationLog) []string {
+	return []string{log.CreatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(log.Id), 10)}
+}
+
+func (commentDB *commentDB) GetModerationLogs(eventId int, pagination *Pagination) ([]models.CommentModerationLog, *PageInfo, error) {
+	var logs []models.CommentModerationLog
+	pageInfo, err := moderationLogKeyset.fetch(commentDB.db.Preload("Actor").Where("event_id = ?", eventId), pagination, &logs, func(i int) []string {
+		return moderationLogKeysetValues(&logs[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return logs, pageInfo, nil
+}
+
+func (commentDB *commentDB) CountUserCommentsSince(userId int, since time.Time) (int64, error) {
+	var total int64
+	err := commentDB.db.Model(&models.Comment{}).
+		Where("user_id = ? AND created_at >= ?", userId, since).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionConflict is returned when a row changed since the caller read it.
+var ErrVersionConflict = errors.New("the resource has been modified by someone else, reload and try again")
+
+// ErrInvalidQuery matches the errors caused by a caller's filters, sort or
+// cursor, as opposed to failures of the database.
+var ErrInvalidQuery = errors.New("invalid query")
+
+type queryError struct {
+	message string
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Is(target error) bool {
+	return target == ErrInvalidQuery
+}
+
+func invalidQuery(format string, args ...interface{}) error {
+	return &queryError{message: fmt.Sprintf(format, args...)}
+}
@@ -3,27 +3,34 @@ package repositories
 import (
 	"time"
 	"together-backend/internal/models"
+	"together-backend/pkg"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepo interface {
-	CreateEvent(title, content string, imageUrl []string, createdBy uint64, startTime, endTime time.Time, location int, detailLocation string) (*models.Event, error)
-	GetEvents(page, size, userId int, search, qType string) ([]models.Event, error)
-	CountEvents(userId int, search, qType string) (int64, error)
+	CreateEvent(title, content string, imageUrl []string, createdBy uint64, startTime, endTime time.Time, location int, detailLocation, status string, publishAt *time.Time) (*models.Event, error)
+	GetEvents(query *EventQuery, pagination *Pagination) ([]models.Event, *PageInfo, error)
+	CountEvents(query *EventQuery) (int64, error)
 	GetEventDetail(eventId int) (models.Event, error)
-	GetEventByEventIdAndCreatedBy(eventId, createdBy int) (models.Event, error)
 	DeleteEvent(event models.Event) (string, error)
 	UpdateEvent(event models.Event, title, content string, imageUrl []string, startTime, endTime time.Time, location int, detailLocation string) (*models.Event, error)
+	UpdateEventStatus(event models.Event, status, reason string) (*models.Event, error)
+	PublishDueEvents(now time.Time) ([]models.Event, error)
+	GetUserSchedule(userId int, from, to time.Time, owner bool) ([]models.Event, error)
+	CountUserEventsSince(userId int, since time.Time) (int64, error)
 }
 
 type eventDB struct {
-	db *gorm.DB
+	db     *gorm.DB
+	search eventSearch
 }
 
 func NewEventRepo(db *gorm.DB) EventRepo {
 	return &eventDB{
-		db: db,
+		db:     db,
+		search: newEventSearch(db),
 	}
 }
 
@@ -37,15 +44,18 @@ func imageUrls(imageUrl []string) []models.EventImage {
 	return eventImageSlice
 }
 
-func (eventDB *eventDB) CreateEvent(title, content string, imageUrl []string, createdBy uint64, startTime, endTime time.Time, location int, detailLocation string) (*models.Event, error) {
+func (eventDB *eventDB) CreateEvent(title, content string, imageUrl []string, createdBy uint64, startTime, endTime time.Time, location int, detailLocation, status string, publishAt *time.Time) (*models.Event, error) {
 	event := models.Event{
 		Title:          title,
 		Content:        content,
+		ContentHTML:    pkg.RenderMarkdown(content),
 		CreatedBy:      createdBy,
 		StartTime:      startTime,
 		EndTime:        endTime,
 		Location:       location,
 		DetailLocation: detailLocation,
+		Status:         status,
+		PublishAt:      publishAt,
 		EventImages:    imageUrls(imageUrl),
 	}
 	if err := eventDB.db.Create(&event).Error; err != nil {
@@ -54,69 +64,30 @@ func (eventDB *eventDB) CreateEvent(title, content string, imageUrl []string, cr
 	return &event, nil
 }
 
-func (eventDB *eventDB) GetEvents(page, size, userId int, search, qType string) ([]models.Event, error) {
-	var (
-		events   []models.Event
-		err      error
-		eventIds []int
-	)
-	if userId != 0 && qType == "created" {
-		err = eventDB.db.Preload("Users").Preload("EventImages").
-			Where("created_by = ?", userId).
-			Limit(size).Offset((page - 1) * size).
-			Order("created_at desc").
-			Find(&events).Error
-	} else if userId != 0 {
-		eventDB.db.Table("user_events").Select("event_id").Where("user_id", userId).Scan(&eventIds)
-		err = eventDB.db.Preload("Users").Preload("EventImages").
-			Where("id IN ?", eventIds).
-			Limit(size).Offset((page - 1) * size).
-			Order("created_at desc").
-			Find(&events).Error
-	} else if search != "" {
-		err = eventDB.db.Preload("Users").Preload("EventImages").
-			Where("title LIKE ?", "%"+search+"%").Or("content LIKE ?", "%"+search+"%").Or("detail_location LIKE ?", "%"+search+"%").
-			Limit(size).Offset((page - 1) * size).
-			Order("created_at desc").
-			Find(&events).Error
-	} else {
-		err = eventDB.db.Preload("Users").Preload("EventImages").
-			Limit(size).Offset((page - 1) * size).
-			Order("created_at desc").
-			Find(&events).Error
-	}
+func (eventDB *eventDB) GetEvents(query *EventQuery, pagination *Pagination) ([]models.Event, *PageInfo, error) {
+	var events []models.Event
+	columns, args := eventDB.columns(query)
+	tx := eventDB.filter(eventDB.db.Model(&models.Event{}), query).Select(columns, args...)
+	k := eventDB.keyset(query)
+	pageInfo, err := k.fetch(tx.Preload("Users").Preload("EventImages"), pagination, &events, func(i int) []string {
+		return keysetValues(k, &events[i])
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if query.Search != "" {
+		eventDB.search.Highlight(events, query.Search)
 	}
 
-	return events, nil
+	return events, pageInfo, nil
 }
 
-func (eventDB *eventDB) CountEvents(userId int, search, qType string) (int64, error) {
-	var (
-		err    error
-		total  int64
-		events models.Event
-	)
-	if userId != 0 && qType == "created" {
-		err = eventDB.db.Where("created_by = ?", userId).
-			Find(&events).Count(&total).Error
-		if err != nil {
-			return int64(0), err
-		}
-	} else if userId != 0 {
-		eventDB.db.Table("user_events").Select("event_id").Where("user_id", userId).Count(&total)
-	} else if search != "" {
-		err = eventDB.db.Where("title LIKE ?", "%"+search+"%").Or("content LIKE ?", "%"+search+"%").Or("detail_location LIKE ?", "%"+search+"%").
-			Find(&events).Count(&total).Error
-		if err != nil {
-			return int64(0), err
-		}
-	} else {
-		err = eventDB.db.Find(&events).Count(&total).Error
-		if err != nil {
-			return int64(0), err
-		}
+func (eventDB *eventDB) CountEvents(query *EventQuery) (int64, error) {
+	var total int64
+	err := eventDB.filter(eventDB.db.Model(&models.Event{}), query).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
 	}
 
 	return total, nil
@@ -133,24 +104,14 @@ func (eventDB *eventDB) GetEventDetail(eventId int) (models.Event, error) {
 	return event, nil
 }
 
-func (eventDB *eventDB) GetEventByEventIdAndCreatedBy(eventId, createdBy int) (models.Event, error) {
-	var event models.Event
-	err := eventDB.db.Preload("Users").Preload("EventImages").
-		First(&event, "id = ? AND created_by = ?", eventId, createdBy).Error
-	if err != nil {
-		return models.Event{}, err
-	}
-
-	return event, nil
-}
-
 func (eventDB *eventDB) DeleteEvent(event models.Event) (string, error) {
-	err := eventDB.db.Delete(&event).Error
-	if err != nil {
-		return "", err
-	}
-	var userEvents models.UserEvent
-	err = eventDB.db.Where("event_id", event.Id).Delete(userEvents).Error
+	err := eventDB.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&event).Error; err != nil {
+			return err
+		}
+		var userEvents models.UserEvent
+		return tx.Where("event_id", event.Id).Delete(userEvents).Error
+	})
 	if err != nil {
 		return "", err
 	}
@@ -163,6 +124,7 @@ func (eventDB *eventDB) UpdateEvent(event models.Event, title, content string, i
 		Id:             event.Id,
 		Title:          title,
 		Content:        content,
+		ContentHTML:    pkg.RenderMarkdown(content),
 		StartTime:      startTime,
 		EndTime:        endTime,
 		Location:       location,
@@ -170,16 +132,95 @@ func (eventDB *eventDB) UpdateEvent(event models.Event, title, content string, i
 		EventImages:    imageUrls(imageUrl),
 	}
 
-	if len(event.EventImages) > 0 {
-		err := eventDB.db.Model(&event.EventImages).Delete(event.EventImages).Error
-		if err != nil {
-			return nil, err
+	err := eventDB.db.Transaction(func(tx *gorm.DB) error {
+		// claim the next version first so a stale update changes nothing
+		result := tx.Model(&models.Event{}).
+			Where("id = ? AND version = ?", event.Id, event.Version).
+			Update("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if len(event.EventImages) > 0 {
+			err := tx.Model(&event.EventImages).Delete(event.EventImages).Error
+			if err != nil {
+				return err
+			}
 		}
+
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Updates(&editEvent).Error
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := eventDB.db.Session(&gorm.Session{FullSaveAssociations: true}).Updates(&editEvent).Error; err != nil {
+	return &event, nil
+}
+
+func (eventDB *eventDB) UpdateEventStatus(event models.Event, status, reason string) (*models.Event, error) {
+	now := time.Now()
+	err := eventDB.db.Model(&event).Updates(map[string]interface{}{
+		"status":            status,
+		"status_reason":     reason,
+		"status_changed_at": now,
+		"version":           gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
 		return nil, err
 	}
 
 	return &event, nil
 }
+
+// PublishDueEvents publishes every draft whose publish_at has passed. The
+// update is a single statement so concurrent runs never publish twice.
+func (eventDB *eventDB) PublishDueEvents(now time.Time) ([]models.Event, error) {
+	var events []models.Event
+	err := eventDB.db.Model(&events).Clauses(clause.Returning{}).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", models.EventStatusDraft, now).
+		Updates(map[string]interface{}{
+			"status":            models.EventStatusPublished,
+			"status_changed_at": now,
+			"version":           gorm.Expr("version + 1"),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetUserSchedule returns the events a user organizes or joined that overlap
+// [from, to), ordered by start time. Cancelled events are left out, and so
+// are drafts and held or hidden events unless the owner reads the schedule.
+func (eventDB *eventDB) GetUserSchedule(userId int, from, to time.Time, owner bool) ([]models.Event, error) {
+	var events []models.Event
+	tx := eventDB.db.
+		Where("(events.created_by = ? OR events.id IN (?))", userId, eventDB.db.Table("user_events").Select("event_id").Where("user_id = ?", userId)).
+		Where("events.start_time < ? AND events.end_time > ?", to, from).
+		Where("events.status <> ?", models.EventStatusCancelled)
+	if !owner {
+		tx = tx.Where("events.status <> ? AND events.hidden_at IS NULL", models.EventStatusDraft)
+	}
+	err := tx.Order("events.start_time, events.id").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (eventDB *eventDB) CountUserEventsSince(userId int, since time.Time) (int64, error) {
+	var total int64
+	err := eventDB.db.Model(&models.Event{}).
+		Where("created_by = ? AND created_at >= ?", userId, since).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return total, nil
+}
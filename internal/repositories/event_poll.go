@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventPollRepo interface {
+	CreatePoll(poll models.EventPoll) (*models.EventPoll, error)
+	GetPolls(eventId int) ([]models.EventPoll, error)
+	GetPoll(pollId, eventId int) (*models.EventPoll, error)
+	ClosePoll(poll *models.EventPoll, closesAt time.Time) (*models.EventPoll, error)
+	MarkApplied(poll *models.EventPoll, appliedAt time.Time) (*models.EventPoll, error)
+	DeletePoll(poll *models.EventPoll) (*models.EventPoll, error)
+	GetVotes(pollIds []uint) ([]models.PollVote, error)
+	ReplaceVotes(pollId, userId int, optionIds []uint) ([]models.PollVote, error)
+}
+
+type eventPollDB struct {
+	db *gorm.DB
+}
+
+func NewEventPollRepo(db *gorm.DB) EventPollRepo {
+	return &eventPollDB{
+		db: db,
+	}
+}
+
+func (eventPollDB *eventPollDB) CreatePoll(poll models.EventPoll) (*models.EventPoll, error) {
+	if err := eventPollDB.db.Create(&poll).Error; err != nil {
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+func (eventPollDB *eventPollDB) GetPolls(eventId int) ([]models.EventPoll, error) {
+	var polls []models.EventPoll
+	err := eventPollDB.db.Preload("Options", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position")
+	}).Where("event_id = ?", eventId).
+		Order("created_at desc, id desc").
+		Find(&polls).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return polls, nil
+}
+
+func (eventPollDB *eventPollDB) GetPoll(pollId, eventId int) (*models.EventPoll, error) {
+	var poll models.EventPoll
+	err := eventPollDB.db.Preload("Options", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("position")
+	}).Where("id = ? AND event_id = ?", pollId, eventId).
+		First(&poll).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &poll, nil
+}
+
+func (eventPollDB *eventPollDB) ClosePoll(poll *models.EventPoll, closesAt time.Time) (*models.EventPoll, error) {
+	if err := eventPollDB.db.Model(poll).Update("closes_at", closesAt).Error; err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (eventPollDB *eventPollDB) MarkApplied(poll *models.EventPoll, appliedAt time.Time) (*models.EventPoll, error) {
+	if err := eventPollDB.db.Model(poll).Update("applied_at", appliedAt).Error; err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+func (eventPollDB *eventPollDB) DeletePoll(poll *models.EventPoll) (*models.EventPoll, error) {
+	if err := eventPollDB.db.Delete(poll).Error; err != nil {
+		return nil, err
+	}
+
+	return poll, nil
+}
+
+// GetVotes loads the votes of several polls at once, with their voters.
+func (eventPollDB *eventPollDB) GetVotes(pollIds []uint) ([]models.PollVote, error) {
+	var votes []models.PollVote
+	if len(pollIds) == 0 {
+		return votes, nil
+	}
+
+	err := eventPollDB.db.Preload("User").
+		Where("poll_id IN ?", pollIds).
+		Order("created_at").
+		Find(&votes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// ReplaceVotes swaps the user's ballot in a poll for optionIds.
+func (eventPollDB *eventPollDB) ReplaceVotes(pollId, userId int, optionIds []uint) ([]models.PollVote, error) {
+	votes := make([]models.PollVote, len(optionIds))
+	for i, optionId := range optionIds {
+		votes[i] = models.PollVote{PollId: uint(pollId), OptionId: optionId, UserId: uint(userId)}
+	}
+
+	err := eventPollDB.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("poll_id = ? AND user_id = ?", pollId, userId).
+			Delete(&models.PollVote{}).Error
+		if err != nil {
+			return err
+		}
+		if len(votes) == 0 {
+			return nil
+		}
+		return tx.Omit("User").Create(&votes).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EventPeriodUpcoming = "upcoming"
+	EventPeriodPast     = "past"
+
+	EventSortStartTime  = "start_time"
+	EventSortPopularity = "popularity"
+	EventSortCreatedAt  = "created_at"
+	EventSortRelevance  = "relevance"
+)
+
+// EventQuery describes which events to list and in which order.
+// Every filter is optional and they are combined with AND. Drafts are
+// only listed for their creator, given as ViewerId.
+type EventQuery struct {
+	ViewerId   int
+	CreatedBy  int
+	AttendeeId int
+	Search     string
+	From       *time.Time
+	To         *time.Time
+	Period     string
+	Location   int
+	Statuses   []string
+	Sort       []EventSort
+}
+
+type EventSort struct {
+	Key  string
+	Desc bool
+}
+
+// ParseEventSort reads a comma separated list of sort keys,
+// a leading "-" means descending, e.g. "-popularity,start_time".
+func ParseEventSort(value string) ([]EventSort, error) {
+	var sorts []EventSort
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		sort := EventSort{Key: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		switch sort.Key {
+		case EventSortStartTime, EventSortPopularity, EventSortCreatedAt, EventSortRelevance:
+		default:
+			return nil, fmt.Errorf("invalid sort key %s", sort.Key)
+		}
+		sorts = append(sorts, sort)
+	}
+	return sorts, nil
+}
+
+func (q *EventQuery) Validate() error {
+	if q.CreatedBy < 0 || q.AttendeeId < 0 {
+		return invalidQuery("invalid user id")
+	}
+	if q.Location < 0 {
+		return invalidQuery("invalid location")
+	}
+	if q.From != nil && q.To != nil && q.From.After(*q.To) {
+		return invalidQuery("from must be less than to")
+	}
+	if q.Period != "" && q.Period != EventPeriodUpcoming && q.Period != EventPeriodPast {
+		return invalidQuery("invalid period %s", q.Period)
+	}
+	for _, status := range q.Statuses {
+		if _, ok := models.EventStatusTransitions[status]; !ok {
+			return invalidQuery("invalid status %s", status)
+		}
+	}
+	for _, sort := range q.Sort {
+		if sort.Key == EventSortRelevance && q.Search == "" {
+			return invalidQuery("sort by relevance requires a search")
+		}
+	}
+	return nil
+}
+
+func (q *EventQuery) sorts() []EventSort {
+	if len(q.Sort) > 0 {
+		return q.Sort
+	}
+	if q.Search != "" {
+		return []EventSort{{Key: EventSortRelevance, Desc: true}, {Key: EventSortCreatedAt, Desc: true}}
+	}
+	return []EventSort{{Key: EventSortCreatedAt, Desc: true}}
+}
+
+// filter applies the WHERE part of the query, shared by GetEvents and CountEvents.
+func (eventDB *eventDB) filter(tx *gorm.DB, q *EventQuery) *gorm.DB {
+	if q.CreatedBy != 0 {
+		tx = tx.Where("events.created_by = ?", q.CreatedBy)
+	}
+	if q.AttendeeId != 0 {
+		tx = tx.Where("events.id IN (?)", eventDB.db.Table("user_events").Select("event_id").Where("user_id = ?", q.AttendeeId))
+	}
+	if q.Search != "" {
+		tx = eventDB.search.Filter(tx, q.Search)
+	}
+	if q.From != nil {
+		tx = tx.Where("events.end_time >= ?", *q.From)
+	}
+	if q.To != nil {
+		tx = tx.Where("events.start_time <= ?", *q.To)
+	}
+	switch q.Period {
+	case EventPeriodUpcoming:
+		tx = tx.Where("events.end_time >= ?", time.Now())
+	case EventPeriodPast:
+		tx = tx.Where("events.end_time < ?", time.Now())
+	}
+	if q.Location != 0 {
+		tx = tx.Where("events.location = ?", q.Location)
+	}
+	if len(q.Statuses) > 0 {
+		tx = tx.Where("events.status IN ?", q.Statuses)
+	}
+	// drafts are listed for their creator only, without a status filter only
+	// among their own events
+	if len(q.Statuses) == 0 && (q.ViewerId == 0 || q.CreatedBy != q.ViewerId) {
+		tx = tx.Where("events.status <> ?", models.EventStatusDraft)
+	} else {
+		tx = tx.Where("(events.status <> ? OR events.created_by = ?)", models.EventStatusDraft, q.ViewerId)
+	}
+	// held or hidden events stay visible to their organizer only
+	return tx.Where("(events.hidden_at IS NULL OR events.created_by = ?)", q.ViewerId)
+}
+
+// popularityExpr counts the attendees of an event.
+const popularityExpr = "(SELECT COUNT(*) FROM user_events WHERE user_events.event_id = events.id)"
+
+// columns returns the select list of GetEvents: the event, plus the search
+// rank and highlights when searching and the attendee count when sorting
+// by popularity, so the keyset values are read from the rows themselves.
+func (eventDB *eventDB) columns(q *EventQuery) (string, []interface{}) {
+	columns := "events.*"
+	var args []interface{}
+	if q.Search != "" {
+		search, searchArgs := eventDB.search.Columns(q.Search)
+		columns += ", " + search
+		args = append(args, searchArgs...)
+	}
+	for _, sort := range q.sorts() {
+		if sort.Key == EventSortPopularity {
+			columns += ", " + popularityExpr + " AS popularity"
+			break
+		}
+	}
+	return columns, args
+}
+
+func (eventDB *eventDB) keyset(q *EventQuery) *keyset {
+	var k keyset
+	for _, sort := range q.sorts() {
+		column := keysetColumn{Name: sort.Key, Desc: sort.Desc}
+		switch sort.Key {
+		case EventSortStartTime, EventSortCreatedAt:
+			column.Order = "events." + sort.Key
+			column.Where = column.Order
+			column.Kind = keysetTime
+		case EventSortPopularity:
+			column.Order = "popularity"
+			column.Where = popularityExpr
+			column.Kind = keysetInt
+		case EventSortRelevance:
+			column.Order = "search_rank"
+			column.Where, column.Args = eventDB.search.RankExpr(q.Search)
+			column.Kind = keysetFloat
+		}
+		k.columns = append(k.columns, column)
+	}
+	k.columns = append(k.columns, keysetColumn{Name: "id", Order: "events.id", Where: "events.id", Kind: keysetInt, Desc: true})
+	return &k
+}
+
+// keysetValues returns the values of event for every column of k.
+func keysetValues(k *keyset, event *models.Event) []string {
+	var values []string
+	for _, column := range k.columns {
+		switch column.Name {
+		case EventSortStartTime:
+			values = append(values, event.StartTime.Format(time.RFC3339Nano))
+		case EventSortCreatedAt:
+			values = append(values, event.CreatedAt.Format(time.RFC3339Nano))
+		case EventSortPopularity:
+			values = append(values, strconv.FormatInt(event.Popularity, 10))
+		case EventSortRelevance:
+			values = append(values, strconv.FormatFloat(event.SearchRank, 'g', -1, 64))
+		case "id":
+			values = append(values, strconv.FormatUint(uint64(event.Id), 10))
+		}
+	}
+	return values
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEventSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []EventSort
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"start_time", []EventSort{{Key: EventSortStartTime}}, false},
+		{"-popularity, start_time", []EventSort{{Key: EventSortPopularity, Desc: true}, {Key: EventSortStartTime}}, false},
+		{"-relevance,,created_at", []EventSort{{Key: EventSortRelevance, Desc: true}, {Key: EventSortCreatedAt}}, false},
+		{"title", nil, true},
+		{"start_time,-id", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseEventSort(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEventSort(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseEventSort(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventQueryValidate(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	tests := []struct {
+		name    string
+		query   EventQuery
+		wantErr bool
+	}{
+		{"empty", EventQuery{}, false},
+		{"everything", EventQuery{CreatedBy: 1, AttendeeId: 2, Location: 3, From: &now, To: &later, Period: EventPeriodUpcoming,
+			Statuses: []string{"published", "draft"}, Search: "go", Sort: []EventSort{{Key: EventSortRelevance, Desc: true}}}, false},
+		{"negative creator", EventQuery{CreatedBy: -1}, true},
+		{"negative attendee", EventQuery{AttendeeId: -1}, true},
+		{"negative location", EventQuery{Location: -1}, true},
+		{"from after to", EventQuery{From: &later, To: &now}, true},
+		{"unknown period", EventQuery{Period: "someday"}, true},
+		{"unknown status", EventQuery{Statuses: []string{"published", "archived"}}, true},
+		{"relevance without search", EventQuery{Sort: []EventSort{{Key: EventSortRelevance}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.wantErr && !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("Validate() = %v, want ErrInvalidQuery", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestEventQueryDrafts(t *testing.T) {
+	tests := []struct {
+		name  string
+		query EventQuery
+		want  string
+	}{
+		{"anonymous", EventQuery{}, "events.status <> $"},
+		{"someone else's events", EventQuery{ViewerId: 1, CreatedBy: 2}, "events.status <> $"},
+		{"own events", EventQuery{ViewerId: 1, CreatedBy: 1}, "(events.status <> $2 OR events.created_by = $3)"},
+		{"by status", EventQuery{ViewerId: 1, Statuses: []string{"draft"}}, "(events.status <> $2 OR events.created_by = $3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			fake.answer([]string{"count"}, []driver.Value{int64(0)})
+			if _, err := NewEventRepo(db).CountEvents(&tt.query); err != nil {
+				t.Fatal(err)
+			}
+			query := fake.queries[0]
+			if !strings.Contains(query, tt.want) {
+				t.Errorf("query %q does not contain %q", query, tt.want)
+			}
+			if n := strings.Count(query, "events.status <>"); n != 1 {
+				t.Errorf("query %q excludes drafts %d times, want once", query, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"strconv"
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventReviewRepo interface {
+	CreateReview(review models.EventReview) (*models.EventReview, error)
+	GetReview(eventId, userId int) (*models.EventReview, error)
+	GetReviews(eventId int, pagination *Pagination) ([]models.EventReview, *PageInfo, error)
+	GetRatingDistribution(eventId int) (map[int]int64, error)
+	GetOrganizerRatings(userIds []uint64) ([]OrganizerRating, error)
+}
+
+// OrganizerRating aggregates the reviews of every event a user created.
+type OrganizerRating struct {
+	UserId      uint64
+	RatingSum   int64
+	Reviews     int64
+	RatedEvents int64
+}
+
+type eventReviewDB struct {
+	db *gorm.DB
+}
+
+func NewEventReviewRepo(db *gorm.DB) EventReviewRepo {
+	return &eventReviewDB{
+		db: db,
+	}
+}
+
+var eventReviewKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "event_reviews.created_at", Where: "event_reviews.created_at", Kind: keysetTime, Desc: true},
+	{Name: "id", Order: "event_reviews.id", Where: "event_reviews.id", Kind: keysetInt, Desc: true},
+}}
+
+func (eventReviewDB *eventReviewDB) CreateReview(review models.EventReview) (*models.EventReview, error) {
+	if err := eventReviewDB.db.Create(&review).Error; err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (eventReviewDB *eventReviewDB) GetReview(eventId, userId int) (*models.EventReview, error) {
+	var review models.EventReview
+	err := eventReviewDB.db.Where("event_id = ? AND user_id = ?", eventId, userId).
+		First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (eventReviewDB *eventReviewDB) GetReviews(eventId int, pagination *Pagination) ([]models.EventReview, *PageInfo, error) {
+	var reviews []models.EventReview
+	pageInfo, err := eventReviewKeyset.fetch(eventReviewDB.db.Preload("User").Where("event_id = ?", eventId), pagination, &reviews, func(i int) []string {
+		return reviewKeysetValues(&reviews[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reviews, pageInfo, nil
+}
+
+func reviewKeysetValues(review *models.EventReview) []string {
+	return []string{review.CreatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(review.Id), 10)}
+}
+
+func (eventReviewDB *eventReviewDB) GetRatingDistribution(eventId int) (map[int]int64, error) {
+	var rows []struct {
+		Rating int
+		Count  int64
+	}
+	err := eventReviewDB.db.Model(&models.EventReview{}).
+		Select("rating, COUNT(*) AS count").
+		Where("event_id = ?", eventId).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		distribution[row.Rating] = row.Count
+	}
+	return distribution, nil
+}
+
+func (eventReviewDB *eventReviewDB) GetOrganizerRatings(userIds []uint64) ([]OrganizerRating, error) {
+	var ratings []OrganizerRating
+	if len(userIds) == 0 {
+		return ratings, nil
+	}
+
+	err := eventReviewDB.db.Model(&models.EventReview{}).
+		Select("events.created_by AS user_id, SUM(event_reviews.rating) AS rating_sum, COUNT(*) AS reviews, COUNT(DISTINCT event_reviews.event_id) AS rated_events").
+		Joins("JOIN events ON events.id = event_reviews.event_id AND events.deleted_at IS NULL").
+		Where("events.created_by IN ?", userIds).
+		Group("events.created_by").
+		Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
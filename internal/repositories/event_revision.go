@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"strconv"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventRevisionRepo interface {
+	CreateRevision(eventId, changedBy int, changes models.FieldChanges) (*models.EventRevision, error)
+	GetRevisions(eventId int, pagination *Pagination) ([]models.EventRevision, *PageInfo, error)
+}
+
+type eventRevisionDB struct {
+	db *gorm.DB
+}
+
+func NewEventRevisionRepo(db *gorm.DB) EventRevisionRepo {
+	return &eventRevisionDB{
+		db: db,
+	}
+}
+
+var eventRevisionKeyset = &keyset{columns: []keysetColumn{
+	{Name: "version", Order: "event_revisions.version", Where: "event_revisions.version", Kind: keysetInt, Desc: true},
+}}
+
+func (eventRevisionDB *eventRevisionDB) CreateRevision(eventId, changedBy int, changes models.FieldChanges) (*models.EventRevision, error) {
+	var version int
+	err := eventRevisionDB.db.Model(&models.EventRevision{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("event_id = ?", eventId).
+		Scan(&version).Error
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.EventRevision{
+		EventId:   uint(eventId),
+		Version:   version + 1,
+		ChangedBy: uint(changedBy),
+		Changes:   changes,
+	}
+	if err := eventRevisionDB.db.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+func (eventRevisionDB *eventRevisionDB) GetRevisions(eventId int, pagination *Pagination) ([]models.EventRevision, *PageInfo, error) {
+	var revisions []models.EventRevision
+	pageInfo, err := eventRevisionKeyset.fetch(eventRevisionDB.db.Preload("User").Where("event_id = ?", eventId), pagination, &revisions, func(i int) []string {
+		return []string{strconv.Itoa(revisions[i].Version)}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return revisions, pageInfo, nil
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventRoleRepo interface {
+	GetEventRole(eventId, userId int) (*models.EventRole, error)
+	GetEventRoles(eventId int) ([]models.EventRole, error)
+	SaveEventRole(eventId, userId int, role string, invitedBy int) (*models.EventRole, error)
+	RemoveEventRole(eventId, userId int) (*models.EventRole, error)
+	TransferOwnership(event models.Event, newOwnerId int) (*models.Event, error)
+}
+
+type eventRoleDB struct {
+	db *gorm.DB
+}
+
+func NewEventRoleRepo(db *gorm.DB) EventRoleRepo {
+	return &eventRoleDB{
+		db: db,
+	}
+}
+
+func (eventRoleDB *eventRoleDB) GetEventRole(eventId, userId int) (*models.EventRole, error) {
+	var eventRole models.EventRole
+	err := eventRoleDB.db.Where("event_id = ? AND user_id = ?", eventId, userId).First(&eventRole).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventRole, nil
+}
+
+func (eventRoleDB *eventRoleDB) GetEventRoles(eventId int) ([]models.EventRole, error) {
+	var eventRoles []models.EventRole
+	err := eventRoleDB.db.Preload("User").
+		Where("event_id = ?", eventId).
+		Order("created_at").
+		Find(&eventRoles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return eventRoles, nil
+}
+
+func (eventRoleDB *eventRoleDB) SaveEventRole(eventId, userId int, role string, invitedBy int) (*models.EventRole, error) {
+	eventRole := models.EventRole{
+		EventId:   uint(eventId),
+		UserId:    uint(userId),
+		Role:      role,
+		InvitedBy: uint(invitedBy),
+	}
+	err := eventRoleDB.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by"}),
+	}).Create(&eventRole).Error
+	if err != nil {
+		return nil, err
+	}
+	eventRoleDB.db.Preload("User").Where("event_id = ? AND user_id = ?", eventId, userId).First(&eventRole)
+
+	return &eventRole, nil
+}
+
+func (eventRoleDB *eventRoleDB) RemoveEventRole(eventId, userId int) (*models.EventRole, error) {
+	eventRole := models.EventRole{
+		EventId: uint(eventId),
+		UserId:  uint(userId),
+	}
+	if err := eventRoleDB.db.Clauses(clause.Returning{}).Delete(&eventRole).Error; err != nil {
+		return nil, err
+	}
+
+	return &eventRole, nil
+}
+
+// TransferOwnership makes newOwnerId the owner and keeps the previous owner as co-organizer.
+func (eventRoleDB *eventRoleDB) TransferOwnership(event models.Event, newOwnerId int) (*models.Event, error) {
+	previousOwnerId := event.CreatedBy
+	err := eventRoleDB.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&event).Updates(map[string]interface{}{
+			"created_by": newOwnerId,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("event_id = ? AND user_id = ?", event.Id, newOwnerId).Delete(&models.EventRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.EventRole{
+			EventId:   event.Id,
+			UserId:    uint(previousOwnerId),
+			Role:      models.EventRoleCoOrganizer,
+			InvitedBy: uint(newOwnerId),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
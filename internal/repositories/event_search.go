@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	highlightStart   = "<mark>"
+	highlightStop    = "</mark>"
+	snippetMaxRunes  = 200
+	searchTextConfig = "together_unaccent"
+)
+
+// ts_headline marks matches with these private-use characters, so the text
+// around them can be HTML-escaped before they become mark tags.
+const (
+	headlineStart = "\ue000"
+	headlineStop  = "\ue001"
+)
+
+var headlineMarks = strings.NewReplacer(headlineStart, highlightStart, headlineStop, highlightStop)
+
+// eventSearch matches and ranks events against a free-text query.
+// Postgres uses the search_vector column (see migrations/001_events_full_text_search.sql),
+// other drivers fall back to LIKE matching.
+type eventSearch interface {
+	Filter(tx *gorm.DB, search string) *gorm.DB
+	RankExpr(search string) (string, []interface{})
+	Columns(search string) (string, []interface{})
+	Highlight(events []models.Event, search string)
+}
+
+func newEventSearch(db *gorm.DB) eventSearch {
+	if db.Dialector != nil && db.Dialector.Name() == "postgres" {
+		return &postgresEventSearch{}
+	}
+	return &likeEventSearch{}
+}
+
+type postgresEventSearch struct{}
+
+const tsQuery = "websearch_to_tsquery(CAST(? AS regconfig), ?)"
+
+func (s *postgresEventSearch) Filter(tx *gorm.DB, search string) *gorm.DB {
+	return tx.Where("events.search_vector @@ "+tsQuery, searchTextConfig, search)
+}
+
+func (s *postgresEventSearch) RankExpr(search string) (string, []interface{}) {
+	return "ts_rank_cd(events.search_vector, " + tsQuery + ")", []interface{}{searchTextConfig, search}
+}
+
+func (s *postgresEventSearch) Columns(search string) (string, []interface{}) {
+	rank, args := s.RankExpr(search)
+	args = append(args,
+		searchTextConfig, searchTextConfig, search,
+		`StartSel="`+headlineStart+`", StopSel="`+headlineStop+`", HighlightAll=true`,
+		searchTextConfig, searchTextConfig, search,
+		`StartSel="`+headlineStart+`", StopSel="`+headlineStop+`", MaxFragments=2, MaxWords=30, MinWords=10`,
+	)
+	return rank + ` AS search_rank,
+		ts_headline(CAST(? AS regconfig), events.title, ` + tsQuery + `, ?) AS title_highlight,
+		ts_headline(CAST(? AS regconfig), events.content, ` + tsQuery + `, ?) AS content_highlight`, args
+}
+
+// Highlight turns the ts_headline results of Columns into HTML: the user's
+// text is escaped and only the marks become tags.
+func (s *postgresEventSearch) Highlight(events []models.Event, search string) {
+	for i := range events {
+		events[i].TitleHighlight = headlineMarks.Replace(html.EscapeString(events[i].TitleHighlight))
+		events[i].ContentHighlight = headlineMarks.Replace(html.EscapeString(events[i].ContentHighlight))
+	}
+}
+
+type likeEventSearch struct{}
+
+func (s *likeEventSearch) Filter(tx *gorm.DB, search string) *gorm.DB {
+	pattern := "%" + strings.ToLower(search) + "%"
+	return tx.Where("(LOWER(events.title) LIKE ? OR LOWER(events.content) LIKE ? OR LOWER(events.detail_location) LIKE ?)",
+		pattern, pattern, pattern)
+}
+
+func (s *likeEventSearch) RankExpr(search string) (string, []interface{}) {
+	pattern := "%" + strings.ToLower(search) + "%"
+	return `(CASE WHEN LOWER(events.title) LIKE ? THEN 1.0 ELSE 0 END +
+		 CASE WHEN LOWER(events.content) LIKE ? THEN 0.4 ELSE 0 END +
+		 CASE WHEN LOWER(events.detail_location) LIKE ? THEN 0.2 ELSE 0 END)`, []interface{}{pattern, pattern, pattern}
+}
+
+func (s *likeEventSearch) Columns(search string) (string, []interface{}) {
+	rank, args := s.RankExpr(search)
+	return rank + " AS search_rank", args
+}
+
+func (s *likeEventSearch) Highlight(events []models.Event, search string) {
+	terms := strings.Fields(search)
+	if len(terms) == 0 {
+		return
+	}
+	for i := range terms {
+		terms[i] = regexp.QuoteMeta(terms[i])
+	}
+	re, err := regexp.Compile("(?i)" + strings.Join(terms, "|"))
+	if err != nil {
+		return
+	}
+
+	for i := range events {
+		events[i].TitleHighlight = markMatches(events[i].Title, re)
+		events[i].ContentHighlight = markMatches(snippet(events[i].Content, re), re)
+	}
+}
+
+// markMatches HTML-escapes text and wraps the matches of re in mark tags.
+func markMatches(text string, re *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString(highlightStart)
+		b.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+		b.WriteString(highlightStop)
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// snippet cuts text down to a window around the first match.
+func snippet(text string, re *regexp.Regexp) string {
+	runes := []rune(text)
+	if len(runes) <= snippetMaxRunes {
+		return text
+	}
+
+	start := 0
+	if loc := re.FindStringIndex(text); loc != nil {
+		start = len([]rune(text[:loc[0]])) - snippetMaxRunes/4
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + snippetMaxRunes
+	if end > len(runes) {
+		end = len(runes)
+		start = end - snippetMaxRunes
+	}
+
+	result := string(runes[start:end])
+	if start > 0 {
+		result = "..." + result
+	}
+	if end < len(runes) {
+		result = result + "..."
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventSessionRepo interface {
+	CreateSession(session models.EventSession) (*models.EventSession, error)
+	GetSessions(eventId, viewerId int) ([]models.EventSession, error)
+	GetSession(sessionId, eventId int) (*models.EventSession, error)
+	UpdateSession(session *models.EventSession) (*models.EventSession, error)
+	DeleteSession(session *models.EventSession) (*models.EventSession, error)
+	CountSessionsOutside(eventId int, startTime, endTime time.Time) (int64, error)
+	GetSignup(sessionId, userId int) (*models.SessionSignup, error)
+	CountSignups(sessionId int) (int64, error)
+	AddSignup(sessionId, userId int) (*models.SessionSignup, error)
+	RemoveSignup(sessionId, userId int) (*models.SessionSignup, error)
+	RemoveUserSignups(userId, eventId int) error
+	GetOverlappingSignups(userId int, startTime, endTime time.Time, excludeSessionId int) ([]models.EventSession, error)
+	CountOverlappingSignups(sessionId int, startTime, endTime time.Time) (int64, error)
+}
+
+type eventSessionDB struct {
+	db *gorm.DB
+}
+
+func NewEventSessionRepo(db *gorm.DB) EventSessionRepo {
+	return &eventSessionDB{
+		db: db,
+	}
+}
+
+func (eventSessionDB *eventSessionDB) CreateSession(session models.EventSession) (*models.EventSession, error) {
+	if err := eventSessionDB.db.Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (eventSessionDB *eventSessionDB) GetSessions(eventId, viewerId int) ([]models.EventSession, error) {
+	var sessions []models.EventSession
+	err := eventSessionDB.db.
+		Select("event_sessions.*, "+
+			"(SELECT COUNT(*) FROM session_signups WHERE session_signups.session_id = event_sessions.id) AS signups, "+
+			"EXISTS (SELECT 1 FROM session_signups WHERE session_signups.session_id = event_sessions.id AND session_signups.user_id = ?) AS signed_up", viewerId).
+		Where("event_id = ?", eventId).
+		Order("start_time, id").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (eventSessionDB *eventSessionDB) GetSession(sessionId, eventId int) (*models.EventSession, error) {
+	var session models.EventSession
+	err := eventSessionDB.db.Where("id = ? AND event_id = ?", sessionId, eventId).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (eventSessionDB *eventSessionDB) UpdateSession(session *models.EventSession) (*models.EventSession, error) {
+	err := eventSessionDB.db.Model(session).
+		Select("title", "speaker", "room", "start_time", "end_time", "capacity").
+		Updates(session).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (eventSessionDB *eventSessionDB) DeleteSession(session *models.EventSession) (*models.EventSession, error) {
+	err := eventSessionDB.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", session.Id).Delete(&models.SessionSignup{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// CountSessionsOutside counts the event's sessions that would fall outside
+// a new event window.
+func (eventSessionDB *eventSessionDB) CountSessionsOutside(eventId int, startTime, endTime time.Time) (int64, error) {
+	var count int64
+	err := eventSessionDB.db.Model(&models.EventSession{}).
+		Where("event_id = ? AND (start_time < ? OR end_time > ?)", eventId, startTime, endTime).
+		Count(&count).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return count, nil
+}
+
+func (eventSessionDB *eventSessionDB) GetSignup(sessionId, userId int) (*models.SessionSignup, error) {
+	var signup models.SessionSignup
+	err := eventSessionDB.db.Where("session_id = ? AND user_id = ?", sessionId, userId).
+		First(&signup).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &signup, nil
+}
+
+func (eventSessionDB *eventSessionDB) CountSignups(sessionId int) (int64, error) {
+	var count int64
+	err := eventSessionDB.db.Model(&models.SessionSignup{}).
+		Where("session_id = ?", sessionId).
+		Count(&count).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return count, nil
+}
+
+func (eventSessionDB *eventSessionDB) AddSignup(sessionId, userId int) (*models.SessionSignup, error) {
+	signup := models.SessionSignup{
+		SessionId: uint(sessionId),
+		UserId:    uint(userId),
+	}
+	if err := eventSessionDB.db.Create(&signup).Error; err != nil {
+		return nil, err
+	}
+
+	return &signup, nil
+}
+
+func (eventSessionDB *eventSessionDB) RemoveSignup(sessionId, userId int) (*models.SessionSignup, error) {
+	signup := models.SessionSignup{
+		SessionId: uint(sessionId),
+		UserId:    uint(userId),
+	}
+	if err := eventSessionDB.db.Delete(&signup).Error; err != nil {
+		return nil, err
+	}
+
+	return &signup, nil
+}
+
+func (eventSessionDB *eventSessionDB) RemoveUserSignups(userId, eventId int) error {
+	return eventSessionDB.db.
+		Where("user_id = ? AND session_id IN (?)", userId, eventSessionDB.db.Model(&models.EventSession{}).Select("id").Where("event_id = ?", eventId)).
+		Delete(&models.SessionSignup{}).Error
+}
+
+// GetOverlappingSignups returns the sessions the user signed up for that
+// overlap [startTime, endTime), in any event.
+func (eventSessionDB *eventSessionDB) GetOverlappingSignups(userId int, startTime, endTime time.Time, excludeSessionId int) ([]models.EventSession, error) {
+	var sessions []models.EventSession
+	err := eventSessionDB.db.Select("event_sessions.*").
+		Joins("JOIN session_signups ON session_signups.session_id = event_sessions.id").
+		Where("session_signups.user_id = ? AND event_sessions.id <> ?", userId, excludeSessionId).
+		Where("event_sessions.start_time < ? AND event_sessions.end_time > ?", endTime, startTime).
+		Order("event_sessions.start_time").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// CountOverlappingSignups counts the users signed up for a session who
+// would clash with another of their sessions if it moved to the new times.
+func (eventSessionDB *eventSessionDB) CountOverlappingSignups(sessionId int, startTime, endTime time.Time) (int64, error) {
+	var count int64
+	err := eventSessionDB.db.Table("session_signups AS s").
+		Select("COUNT(DISTINCT s.user_id)").
+		Joins("JOIN session_signups AS o ON o.user_id = s.user_id AND o.session_id <> s.session_id").
+		Joins("JOIN event_sessions ON event_sessions.id = o.session_id AND event_sessions.deleted_at IS NULL").
+		Where("s.session_id = ?", sessionId).
+		Where("event_sessions.start_time < ? AND event_sessions.end_time > ?", endTime, startTime).
+		Scan(&count).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return count, nil
+}
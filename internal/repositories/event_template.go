@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventTemplateRepo interface {
+	CreateTemplate(template models.EventTemplate) (*models.EventTemplate, error)
+	GetTemplates(userId int) ([]models.EventTemplate, error)
+	GetTemplate(templateId, userId int) (*models.EventTemplate, error)
+	DeleteTemplate(template *models.EventTemplate) (*models.EventTemplate, error)
+}
+
+type eventTemplateDB struct {
+	db *gorm.DB
+}
+
+func NewEventTemplateRepo(db *gorm.DB) EventTemplateRepo {
+	return &eventTemplateDB{
+		db: db,
+	}
+}
+
+func (eventTemplateDB *eventTemplateDB) CreateTemplate(template models.EventTemplate) (*models.EventTemplate, error) {
+	if err := eventTemplateDB.db.Create(&template).Error; err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (eventTemplateDB *eventTemplateDB) GetTemplates(userId int) ([]models.EventTemplate, error) {
+	var templates []models.EventTemplate
+	err := eventTemplateDB.db.Where("user_id = ?", userId).
+		Order("name").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (eventTemplateDB *eventTemplateDB) GetTemplate(templateId, userId int) (*models.EventTemplate, error) {
+	var template models.EventTemplate
+	err := eventTemplateDB.db.Where("id = ? AND user_id = ?", templateId, userId).
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (eventTemplateDB *eventTemplateDB) DeleteTemplate(template *models.EventTemplate) (*models.EventTemplate, error) {
+	if err := eventTemplateDB.db.Delete(template).Error; err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeDriver is a database/sql driver that records the statements and
+// transactions it is given and answers every query with the same rows, so
+// repositories run without a database.
+type fakeDriver struct {
+	mu        sync.Mutex
+	queries   []string
+	args      [][]interface{}
+	columns   []string
+	rows      [][]driver.Value
+	begins    int
+	commits   int
+	rollbacks int
+	isolation driver.IsolationLevel
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("fake", testDriver)
+}
+
+// newFakeDB opens a Postgres flavoured gorm handle on the fake driver and
+// resets what it recorded.
+func newFakeDB(t *testing.T) (*gorm.DB, *fakeDriver) {
+	t.Helper()
+	testDriver.mu.Lock()
+	testDriver.queries, testDriver.args = nil, nil
+	testDriver.columns, testDriver.rows = nil, nil
+	testDriver.begins, testDriver.commits, testDriver.rollbacks = 0, 0, 0
+	testDriver.isolation = 0
+	testDriver.mu.Unlock()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DriverName: "fake"}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, testDriver
+}
+
+// answer sets the rows every following query returns.
+func (d *fakeDriver) answer(columns []string, rows ...[]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.columns, d.rows = columns, rows
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.begins++
+	c.d.isolation = opts.Isolation
+	return &fakeTx{d: c.d}, nil
+}
+
+func (c *fakeConn) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.d.queries = append(c.d.queries, query)
+	c.d.args = append(c.d.args, values)
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.record(query, args)
+	return &fakeRows{columns: c.d.columns, rows: c.d.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+type fakeTx struct {
+	d *fakeDriver
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
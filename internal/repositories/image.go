@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"strconv"
+	"time"
 	"together-backend/internal/models"
 
 	"gorm.io/gorm"
@@ -9,6 +11,7 @@ import (
 type ImageRepo interface {
 	DeleteImageByEventId(eventId int) (string, error)
 	GetImageByUrl(url string) (models.EventImage, error)
+	GetEventGallery(eventId int, pagination *Pagination) ([]models.GalleryImage, *PageInfo, error)
 }
 
 type imageDB struct {
@@ -36,3 +39,38 @@ func (imageDB *imageDB) DeleteImageByEventId(eventId int) (string, error) {
 	}
 	return "deleted successfully", nil
 }
+
+// galleryKeyset pages newest first. Ids repeat across the two sources, so
+// the source breaks ties before the id does.
+var galleryKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "gallery.created_at", Where: "gallery.created_at", Kind: keysetTime, Desc: true},
+	{Name: "source", Order: "gallery.source", Where: "gallery.source", Desc: true},
+	{Name: "id", Order: "gallery.id", Where: "gallery.id", Kind: keysetInt, Desc: true},
+}}
+
+func galleryKeysetValues(image *models.GalleryImage) []string {
+	return []string{image.CreatedAt.Format(time.RFC3339Nano), image.Source, strconv.FormatUint(uint64(image.Id), 10)}
+}
+
+// GetEventGallery lists an event's own images together with the images of
+// its visible comments.
+func (imageDB *imageDB) GetEventGallery(eventId int, pagination *Pagination) ([]models.GalleryImage, *PageInfo, error) {
+	eventImages := imageDB.db.Table("event_images").
+		Select("event_images.id, ? AS source, event_images.image_url, NULL AS comment_id, events.created_by AS user_id, event_images.created_at", models.GalleryImageSourceEvent).
+		Joins("JOIN events ON events.id = event_images.event_id").
+		Where("event_images.event_id = ? AND event_images.deleted_at IS NULL", eventId)
+	commentImages := imageDB.db.Table("comment_images").
+		Select("comment_images.id, ? AS source, comment_images.image_url, comment_images.comment_id, comments.user_id, comment_images.created_at", models.GalleryImageSourceComment).
+		Joins("JOIN comments ON comments.id = comment_images.comment_id").
+		Where("comment_images.event_id = ? AND comment_images.deleted_at IS NULL AND comments.hidden_at IS NULL AND comments.deleted_at IS NULL", eventId)
+
+	var images []models.GalleryImage
+	pageInfo, err := galleryKeyset.fetch(imageDB.db.Table("(? UNION ALL ?) AS gallery", eventImages, commentImages).Preload("User"), pagination, &images, func(i int) []string {
+		return galleryKeysetValues(&images[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return images, pageInfo, nil
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ModerationRepo interface {
+	HoldContent(item *models.ModerationItem, hiddenAt time.Time) (*models.ModerationItem, error)
+	GetOpenItem(targetType string, targetId uint) (*models.ModerationItem, error)
+	CreateItem(item *models.ModerationItem) (*models.ModerationItem, error)
+	UpdateItem(item *models.ModerationItem, updates map[string]interface{}) (*models.ModerationItem, error)
+	GetItem(itemId int) (*models.ModerationItem, error)
+	GetItems(filter *ModerationFilter, pagination *Pagination) ([]models.ModerationItem, *PageInfo, error)
+	CreateReport(report *models.ModerationReport) (*models.ModerationReport, error)
+	HasReported(itemId uint, reporterId int) (bool, error)
+	CountReports(itemId uint) (int64, error)
+	SetTargetHidden(targetType string, targetId uint, hiddenAt *time.Time) (bool, error)
+}
+
+// ModerationFilter narrows the moderation queue. Zero values match all.
+type ModerationFilter struct {
+	Status     string
+	TargetType string
+	Source     string
+	Category   string
+	EventId    int
+	AssigneeId int
+	Unassigned bool
+}
+
+type moderationDB struct {
+	db *gorm.DB
+}
+
+func NewModerationRepo(db *gorm.DB) ModerationRepo {
+	return &moderationDB{
+		db: db,
+	}
+}
+
+// moderationTargets maps the target types that can be hidden to the tables
+// holding them.
+var moderationTargets = map[string]interface{}{
+	models.ModerationTargetEvent:   &models.Event{},
+	models.ModerationTargetComment: &models.Comment{},
+}
+
+const reportCountSelect = "moderation_items.*, (SELECT COUNT(*) FROM moderation_reports WHERE moderation_reports.item_id = moderation_items.id) AS report_count"
+
+// HoldContent hides the target of the item and queues it for review. A
+// target already waiting in the queue keeps its open item.
+func (moderationDB *moderationDB) HoldContent(item *models.ModerationItem, hiddenAt time.Time) (*models.ModerationItem, error) {
+	err := moderationDB.db.Transaction(func(tx *gorm.DB) error {
+		repo := NewModerationRepo(tx)
+		hidden, err := repo.SetTargetHidden(item.TargetType, item.TargetId, &hiddenAt)
+		if err != nil {
+			return err
+		}
+
+		open, err := repo.GetOpenItem(item.TargetType, item.TargetId)
+		if err != nil && err.Error() != "record not found" {
+			return err
+		}
+		if open == nil {
+			item.TargetHidden = hidden
+			_, err := repo.CreateItem(item)
+			return err
+		}
+
+		*item = *open
+		if hidden {
+			_, err = repo.UpdateItem(item, map[string]interface{}{"target_hidden": true})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (moderationDB *moderationDB) GetOpenItem(targetType string, targetId uint) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	err := moderationDB.db.
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetId, models.ModerationStatusPending).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (moderationDB *moderationDB) CreateItem(item *models.ModerationItem) (*models.ModerationItem, error) {
+	if err := moderationDB.db.Create(item).Error; err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (moderationDB *moderationDB) UpdateItem(item *models.ModerationItem, updates map[string]interface{}) (*models.ModerationItem, error) {
+	if err := moderationDB.db.Model(item).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (moderationDB *moderationDB) GetItem(itemId int) (*models.ModerationItem, error) {
+	var item models.ModerationItem
+	err := moderationDB.db.Select(reportCountSelect).
+		Preload("Reports", func(db *gorm.DB) *gorm.DB {
+			return db.Order("moderation_reports.created_at, moderation_reports.id")
+		}).
+		Preload("Reports.Reporter").
+		First(&item, itemId).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// the queue is worked oldest first
+var moderationItemKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "moderation_items.created_at", Where: "moderation_items.created_at", Kind: keysetTime},
+	{Name: "id", Order: "moderation_items.id", Where: "moderation_items.id", Kind: keysetInt},
+}}
+
+func moderationItemKeysetValues(item *models.ModerationItem) []string {
+	return []string{item.CreatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(item.Id), 10)}
+}
+
+func (moderationDB *moderationDB) GetItems(filter *ModerationFilter, pagination *Pagination) ([]models.ModerationItem, *PageInfo, error) {
+	var items []models.ModerationItem
+	tx := moderationDB.db.Select(reportCountSelect)
+	if filter.Status != "" {
+		tx = tx.Where("moderation_items.status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		tx = tx.Where("moderation_items.target_type = ?", filter.TargetType)
+	}
+	if filter.Source != "" {
+		tx = tx.Where("moderation_items.source = ?", filter.Source)
+	}
+	if filter.Category != "" {
+		tx = tx.Where("moderation_items.id IN (?)", moderationDB.db.Model(&models.ModerationReport{}).Select("item_id").Where("category = ?", filter.Category))
+	}
+	if filter.EventId != 0 {
+		tx = tx.Where("moderation_items.event_id = ?", filter.EventId)
+	}
+	if filter.Unassigned {
+		tx = tx.Where("moderation_items.assignee_id IS NULL")
+	} else if filter.AssigneeId != 0 {
+		tx = tx.Where("moderation_items.assignee_id = ?", filter.AssigneeId)
+	}
+
+	pageInfo, err := moderationItemKeyset.fetch(tx, pagination, &items, func(i int) []string {
+		return moderationItemKeysetValues(&items[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items, pageInfo, nil
+}
+
+func (moderationDB *moderationDB) CreateReport(report *models.ModerationReport) (*models.ModerationReport, error) {
+	if err := moderationDB.db.Create(report).Error; err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (moderationDB *moderationDB) HasReported(itemId uint, reporterId int) (bool, error) {
+	var total int64
+	err := moderationDB.db.Model(&models.ModerationReport{}).
+		Where("item_id = ? AND reporter_id = ?", itemId, reporterId).
+		Count(&total).Error
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+func (moderationDB *moderationDB) CountReports(itemId uint) (int64, error) {
+	var total int64
+	err := moderationDB.db.Model(&models.ModerationReport{}).
+		Where("item_id = ?", itemId).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return total, nil
+}
+
+// SetTargetHidden hides the target, or shows it again when hiddenAt is nil,
+// and reports whether it changed.
+func (moderationDB *moderationDB) SetTargetHidden(targetType string, targetId uint, hiddenAt *time.Time) (bool, error) {
+	target, ok := moderationTargets[targetType]
+	if !ok {
+		return false, fmt.Errorf("%s can't be hidden", targetType)
+	}
+
+	tx := moderationDB.db.Model(target).Where("id = ?", targetId)
+	if hiddenAt != nil {
+		tx = tx.Where("hidden_at IS NULL")
+	} else {
+		tx = tx.Where("hidden_at IS NOT NULL")
+	}
+	result := tx.Update("hidden_at", hiddenAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
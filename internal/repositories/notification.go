@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"strconv"
+	"time"
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepo interface {
+	CreateNotifications(notifications []models.Notification) error
+	GetNotifications(userId int, unreadOnly bool, pagination *Pagination) ([]models.Notification, *PageInfo, error)
+	CountUnread(userId int) (int64, error)
+	MarkRead(userId int, ids []int) (int64, error)
+}
+
+type notificationDB struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepo(db *gorm.DB) NotificationRepo {
+	return &notificationDB{
+		db: db,
+	}
+}
+
+var notificationKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "notifications.created_at", Where: "notifications.created_at", Kind: keysetTime, Desc: true},
+	{Name: "id", Order: "notifications.id", Where: "notifications.id", Kind: keysetInt, Desc: true},
+}}
+
+func notificationKeysetValues(notification *models.Notification) []string {
+	return []string{notification.CreatedAt.Format(time.RFC3339Nano), strconv.FormatUint(uint64(notification.Id), 10)}
+}
+
+func (notificationDB *notificationDB) CreateNotifications(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return notificationDB.db.Create(&notifications).Error
+}
+
+func (notificationDB *notificationDB) GetNotifications(userId int, unreadOnly bool, pagination *Pagination) ([]models.Notification, *PageInfo, error) {
+	var notifications []models.Notification
+	tx := notificationDB.db.Where("user_id = ?", userId)
+	if unreadOnly {
+		tx = tx.Where("read_at IS NULL")
+	}
+	pageInfo, err := notificationKeyset.fetch(tx, pagination, &notifications, func(i int) []string {
+		return notificationKeysetValues(&notifications[i])
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return notifications, pageInfo, nil
+}
+
+func (notificationDB *notificationDB) CountUnread(userId int) (int64, error) {
+	var total int64
+	err := notificationDB.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Count(&total).Error
+	if err != nil {
+		return int64(0), err
+	}
+
+	return total, nil
+}
+
+// MarkRead marks the given notifications of the user as read, or all of them when ids is empty.
+func (notificationDB *notificationDB) MarkRead(userId int, ids []int) (int64, error) {
+	tx := notificationDB.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId)
+	if len(ids) > 0 {
+		tx = tx.Where("id IN ?", ids)
+	}
+	result := tx.Update("read_at", time.Now())
+	if result.Error != nil {
+		return int64(0), result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageLimit = 8
+	MaxPageLimit     = 50
+)
+
+const (
+	keysetTime  = "time"
+	keysetInt   = "int"
+	keysetFloat = "float"
+)
+
+// Pagination selects a page either by opaque cursor (preferred) or,
+// for legacy clients, by page number.
+type Pagination struct {
+	Page   int
+	Limit  int
+	Cursor string
+}
+
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+func (p *Pagination) Normalize() {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Page < 1 {
+		p.Page = 1
+	}
+}
+
+type cursor struct {
+	Values []string `json:"v"`
+	Before bool     `json:"b,omitempty"`
+	Sort   string   `json:"s"`
+}
+
+type keysetColumn struct {
+	Name  string
+	Order string
+	Where string
+	Args  []interface{}
+	Kind  string
+	Desc  bool
+}
+
+// keyset pages through rows ordered by columns, the last column must be unique.
+type keyset struct {
+	columns []keysetColumn
+}
+
+func (k *keyset) signature() string {
+	var parts []string
+	for _, column := range k.columns {
+		if column.Desc {
+			parts = append(parts, "-"+column.Name)
+		} else {
+			parts = append(parts, column.Name)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (k *keyset) decode(value string) (*cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalidQuery("invalid cursor")
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, invalidQuery("invalid cursor")
+	}
+	if c.Sort != k.signature() || len(c.Values) != len(k.columns) {
+		return nil, invalidQuery("cursor does not match the sort order")
+	}
+	return &c, nil
+}
+
+func (k *keyset) encode(values []string, before bool) string {
+	raw, _ := json.Marshal(cursor{Values: values, Before: before, Sort: k.signature()})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (k *keyset) value(column keysetColumn, value string) (interface{}, error) {
+	switch column.Kind {
+	case keysetTime:
+		return time.Parse(time.RFC3339Nano, value)
+	case keysetInt:
+		return strconv.ParseInt(value, 10, 64)
+	case keysetFloat:
+		return strconv.ParseFloat(value, 64)
+	}
+	return value, nil
+}
+
+// apply adds the keyset condition, ordering and limit to tx. One extra row
+// is requested so the caller can tell whether another page exists.
+func (k *keyset) apply(tx *gorm.DB, p *Pagination) (*gorm.DB, *cursor, error) {
+	c, err := k.decode(p.Cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	before := c != nil && c.Before
+
+	if c != nil {
+		var (
+			ors  []string
+			vars []interface{}
+		)
+		for i := range k.columns {
+			var ands []string
+			for j := 0; j <= i; j++ {
+				column := k.columns[j]
+				value, err := k.value(column, c.Values[j])
+				if err != nil {
+					return nil, nil, invalidQuery("invalid cursor")
+				}
+				op := "="
+				if j == i {
+					op = ">"
+					if column.Desc != before {
+						op = "<"
+					}
+				}
+				ands = append(ands, column.Where+" "+op+" ?")
+				vars = append(vars, column.Args...)
+				vars = append(vars, value)
+			}
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+		tx = tx.Where("("+strings.Join(ors, " OR ")+")", vars...)
+	} else if p.Page > 1 {
+		tx = tx.Offset((p.Page - 1) * p.Limit)
+	}
+
+	for _, column := range k.columns {
+		if column.Desc != before {
+			tx = tx.Order(column.Order + " desc")
+		} else {
+			tx = tx.Order(column.Order)
+		}
+	}
+
+	return tx.Limit(p.Limit + 1), c, nil
+}
+
+// fetch runs the keyset query on tx into dest, a pointer to a slice. It
+// drops the extra row, restores the order of a page read backwards and
+// builds the page info, reading the keyset values of row i with values.
+func (k *keyset) fetch(tx *gorm.DB, p *Pagination, dest interface{}, values func(i int) []string) (*PageInfo, error) {
+	tx, c, err := k.apply(tx, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	hasMore := rows.Len() > p.Limit
+	if hasMore {
+		rows.Set(rows.Slice(0, p.Limit))
+	}
+	n := rows.Len()
+	if c != nil && c.Before {
+		swap := reflect.Swapper(rows.Interface())
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	var first, last []string
+	if n > 0 {
+		first = values(0)
+		last = values(n - 1)
+	}
+	return k.pageInfo(p, c, hasMore, first, last), nil
+}
+
+// pageInfo builds the cursors around a fetched page. first and last are
+// the keyset values of the first and last row after trimming and reversing.
+func (k *keyset) pageInfo(p *Pagination, c *cursor, hasMore bool, first, last []string) *PageInfo {
+	info := PageInfo{Limit: p.Limit}
+	if first == nil {
+		return &info
+	}
+	before := c != nil && c.Before
+	if hasMore || before {
+		info.NextCursor = k.encode(last, false)
+	}
+	if (before && hasMore) || (!before && (c != nil || p.Page > 1)) {
+		info.PrevCursor = k.encode(first, true)
+	}
+	return &info
+}
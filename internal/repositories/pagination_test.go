@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var testKeyset = &keyset{columns: []keysetColumn{
+	{Name: "created_at", Order: "items.created_at", Where: "items.created_at", Kind: keysetTime, Desc: true},
+	{Name: "id", Order: "items.id", Where: "items.id", Kind: keysetInt, Desc: true},
+}}
+
+var idKeyset = &keyset{columns: []keysetColumn{
+	{Name: "id", Order: "items.id", Where: "items.id", Kind: keysetInt, Desc: true},
+}}
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	values := []string{"2024-05-01T10:00:00Z", "42"}
+	for _, before := range []bool{false, true} {
+		c, err := testKeyset.decode(testKeyset.encode(values, before))
+		if err != nil {
+			t.Fatalf("decode(encode(%v, %v)) failed: %s", values, before, err)
+		}
+		if !reflect.DeepEqual(c.Values, values) || c.Before != before {
+			t.Errorf("decode(encode(%v, %v)) = %v, %v", values, before, c.Values, c.Before)
+		}
+	}
+
+	c, err := testKeyset.decode("")
+	if c != nil || err != nil {
+		t.Errorf("decode(\"\") = %v, %v, want no cursor", c, err)
+	}
+}
+
+func TestKeysetDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "not a cursor!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("{"))},
+		{"other sort order", idKeyset.encode([]string{"42"}, false)},
+		{"missing values", base64.RawURLEncoding.EncodeToString([]byte(`{"v":["42"],"s":"-created_at,-id"}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := testKeyset.decode(tt.value); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("decode(%q) = %v, want ErrInvalidQuery", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestKeysetApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+		page   int
+		where  string
+		order  string
+	}{
+		{"first page", "", 1, "", "ORDER BY items.created_at desc,items.id desc LIMIT 3"},
+		{"after", testKeyset.encode([]string{"2024-05-01T10:00:00Z", "42"}, false),
+			1, "WHERE ((items.created_at < $1) OR (items.created_at = $2 AND items.id < $3))", "ORDER BY items.created_at desc,items.id desc LIMIT 3"},
+		{"before", testKeyset.encode([]string{"2024-05-01T10:00:00Z", "42"}, true),
+			1, "WHERE ((items.created_at > $1) OR (items.created_at = $2 AND items.id > $3))", "ORDER BY items.created_at,items.id LIMIT 3"},
+		{"legacy page", "", 3, "", "ORDER BY items.created_at desc,items.id desc LIMIT 3 OFFSET 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			tx, _, err := testKeyset.apply(db.Table("items"), &Pagination{Page: tt.page, Limit: 2, Cursor: tt.cursor})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rows []map[string]interface{}
+			if err := tx.Find(&rows).Error; err != nil {
+				t.Fatal(err)
+			}
+			query := fake.queries[0]
+			if tt.where != "" && !strings.Contains(query, tt.where) {
+				t.Errorf("query %q does not contain %q", query, tt.where)
+			}
+			if !strings.HasSuffix(query, tt.order) {
+				t.Errorf("query %q does not end with %q", query, tt.order)
+			}
+		})
+	}
+}
+
+func TestKeysetApplyInvalidValue(t *testing.T) {
+	db, _ := newFakeDB(t)
+	cursor := testKeyset.encode([]string{"yesterday", "42"}, false)
+	if _, _, err := testKeyset.apply(db.Table("items"), &Pagination{Limit: 2, Cursor: cursor}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("apply with a bad cursor value = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestKeysetPageInfo(t *testing.T) {
+	after := &cursor{Values: []string{"5"}}
+	before := &cursor{Values: []string{"5"}, Before: true}
+	tests := []struct {
+		name     string
+		page     int
+		cursor   *cursor
+		hasMore  bool
+		empty    bool
+		wantNext bool
+		wantPrev bool
+	}{
+		{"only page", 1, nil, false, false, false, false},
+		{"first of many", 1, nil, true, false, true, false},
+		{"middle going forward", 1, after, true, false, true, true},
+		{"last going forward", 1, after, false, false, false, true},
+		{"middle going back", 1, before, true, false, true, true},
+		{"first going back", 1, before, false, false, true, false},
+		{"legacy page", 2, nil, false, false, false, true},
+		{"empty", 1, after, false, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last := []string{"9"}, []string{"7"}
+			if tt.empty {
+				first, last = nil, nil
+			}
+			info := idKeyset.pageInfo(&Pagination{Page: tt.page, Limit: 3}, tt.cursor, tt.hasMore, first, last)
+			if (info.NextCursor != "") != tt.wantNext || (info.PrevCursor != "") != tt.wantPrev {
+				t.Fatalf("pageInfo = next %q, prev %q, want next %v, prev %v", info.NextCursor, info.PrevCursor, tt.wantNext, tt.wantPrev)
+			}
+			if tt.wantNext {
+				if c, _ := idKeyset.decode(info.NextCursor); c.Before || c.Values[0] != "7" {
+					t.Errorf("next cursor = %+v, want after 7", c)
+				}
+			}
+			if tt.wantPrev {
+				if c, _ := idKeyset.decode(info.PrevCursor); !c.Before || c.Values[0] != "9" {
+					t.Errorf("prev cursor = %+v, want before 9", c)
+				}
+			}
+		})
+	}
+}
+
+func TestKeysetFetch(t *testing.T) {
+	type item struct {
+		Id int64
+	}
+	tests := []struct {
+		name     string
+		cursor   string
+		fetched  []int64
+		want     []int64
+		wantNext string
+		wantPrev string
+	}{
+		{"first page", "", []int64{9, 8, 7}, []int64{9, 8}, "8", ""},
+		{"last page", idKeyset.encode([]string{"8"}, false), []int64{7, 6}, []int64{7, 6}, "", "7"},
+		{"going back", idKeyset.encode([]string{"7"}, true), []int64{8, 9, 10}, []int64{9, 8}, "8", "9"},
+		{"empty", idKeyset.encode([]string{"1"}, false), nil, nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			var rows [][]driver.Value
+			for _, id := range tt.fetched {
+				rows = append(rows, []driver.Value{id})
+			}
+			fake.answer([]string{"id"}, rows...)
+
+			var items []item
+			info, err := idKeyset.fetch(db.Table("items"), &Pagination{Page: 1, Limit: 2, Cursor: tt.cursor}, &items, func(i int) []string {
+				return []string{strconv.FormatInt(items[i].Id, 10)}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int64
+			for _, item := range items {
+				got = append(got, item.Id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fetch returned %v, want %v", got, tt.want)
+			}
+			if next := cursorValue(info.NextCursor); next != tt.wantNext {
+				t.Errorf("next cursor at %q, want %q", next, tt.wantNext)
+			}
+			if prev := cursorValue(info.PrevCursor); prev != tt.wantPrev {
+				t.Errorf("prev cursor at %q, want %q", prev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+func cursorValue(value string) string {
+	c, err := idKeyset.decode(value)
+	if err != nil || c == nil {
+		return ""
+	}
+	return c.Values[0]
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"together-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepo interface {
+	ToggleReaction(targetType string, targetId uint, userId int, emoji string) (bool, error)
+	GetReactionCounts(targetType string, targetIds []uint, viewerId int) (map[uint][]models.ReactionCount, error)
+}
+
+type reactionDB struct {
+	db *gorm.DB
+}
+
+func NewReactionRepo(db *gorm.DB) ReactionRepo {
+	return &reactionDB{
+		db: db,
+	}
+}
+
+// ToggleReaction removes the user's reaction if present, otherwise adds it,
+// and reports whether it was added.
+func (reactionDB *reactionDB) ToggleReaction(targetType string, targetId uint, userId int, emoji string) (bool, error) {
+	added := false
+	err := reactionDB.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("target_type = ? AND target_id = ? AND user_id = ? AND emoji = ?", targetType, targetId, userId, emoji).
+			Delete(&models.Reaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		// a concurrent toggle may have added the same reaction first, which
+		// leaves it added either way
+		added = true
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Reaction{
+			TargetType: targetType,
+			TargetId:   targetId,
+			UserId:     uint(userId),
+			Emoji:      emoji,
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+// GetReactionCounts aggregates the reactions of many targets in one query,
+// keyed by target id and ordered as models.ReactionEmojis.
+func (reactionDB *reactionDB) GetReactionCounts(targetType string, targetIds []uint, viewerId int) (map[uint][]models.ReactionCount, error) {
+	counts := make(map[uint][]models.ReactionCount, len(targetIds))
+	if len(targetIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TargetId    uint
+		Emoji       string
+		Count       int64
+		ReactedByMe bool
+	}
+	err := reactionDB.db.Model(&models.Reaction{}).
+		Select("target_id, emoji, COUNT(*) AS count, COUNT(CASE WHEN user_id = ? THEN 1 END) > 0 AS reacted_by_me", viewerId).
+		Where("target_type = ? AND target_id IN ?", targetType, targetIds).
+		Group("target_id, emoji").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byEmoji := make(map[uint]map[string]models.ReactionCount)
+	for _, row := range rows {
+		if byEmoji[row.TargetId] == nil {
+			byEmoji[row.TargetId] = make(map[string]models.ReactionCount)
+		}
+		byEmoji[row.TargetId][row.Emoji] = models.ReactionCount{Emoji: row.Emoji, Count: row.Count, ReactedByMe: row.ReactedByMe}
+	}
+	for targetId, emojis := range byEmoji {
+		for _, emoji := range models.ReactionEmojis {
+			if count, ok := emojis[emoji]; ok {
+				counts[targetId] = append(counts[targetId], count)
+			}
+		}
+	}
+	return counts, nil
+}
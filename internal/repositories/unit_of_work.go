@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxTransactionRetries = 3
+
+// Repositories bundles the repositories sharing one database handle,
+// inside a UnitOfWork that handle is the transaction.
+type Repositories struct {
+	Events         EventRepo
+	EventRoles     EventRoleRepo
+	EventRevisions EventRevisionRepo
+	EventReviews   EventReviewRepo
+	EventSessions  EventSessionRepo
+	EventPolls     EventPollRepo
+	Reactions      ReactionRepo
+	Users          UserRepo
+	Images         ImageRepo
+	UserEvents     UserEventRepo
+	Notifications  NotificationRepo
+	Comments       CommentRepo
+	Moderation     ModerationRepo
+}
+
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Events:         NewEventRepo(db),
+		EventRoles:     NewEventRoleRepo(db),
+		EventRevisions: NewEventRevisionRepo(db),
+		EventReviews:   NewEventReviewRepo(db),
+		EventSessions:  NewEventSessionRepo(db),
+		EventPolls:     NewEventPollRepo(db),
+		Reactions:      NewReactionRepo(db),
+		Users:          NewUserRepo(db),
+		Images:         NewImageRepo(db),
+		UserEvents:     NewUserEventRepo(db),
+		Notifications:  NewNotificationRepo(db),
+		Comments:       NewCommentRepo(db),
+		Moderation:     NewModerationRepo(db),
+	}
+}
+
+// UnitOfWork runs fn in a serializable transaction with repositories bound
+// to it, commits when fn returns nil and retries on serialization failures.
+type UnitOfWork interface {
+	Do(fn func(repos *Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{
+		db: db,
+	}
+}
+
+func (uow *unitOfWork) Do(fn func(repos *Repositories) error) error {
+	var err error
+	for attempt := 0; attempt <= maxTransactionRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt*attempt) * 10 * time.Millisecond)
+		}
+		err = uow.db.Transaction(func(tx *gorm.DB) error {
+			return fn(NewRepositories(tx))
+		}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+		if !isRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isRetryable reports serialization failures and deadlocks (SQLSTATE 40001, 40P01).
+func isRetryable(err error) bool {
+	var sqlErr interface{ SQLState() string }
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	return sqlErr.SQLState() == "40001" || sqlErr.SQLState() == "40P01"
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sqlstate " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"serialization failure", sqlStateError("40001"), true},
+		{"deadlock", sqlStateError("40P01"), true},
+		{"unique violation", sqlStateError("23505"), false},
+		{"wrapped serialization failure", fmt.Errorf("commit: %w", sqlStateError("40001")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitOfWorkDo(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name      string
+		errs      []error
+		want      error
+		attempts  int
+		commits   int
+		rollbacks int
+	}{
+		{"commits", []error{nil}, nil, 1, 1, 0},
+		{"retries a serialization failure", []error{sqlStateError("40001"), nil}, nil, 2, 1, 1},
+		{"retries a deadlock", []error{sqlStateError("40P01"), sqlStateError("40P01"), nil}, nil, 3, 1, 2},
+		{"gives up after the last retry", []error{sqlStateError("40001")}, sqlStateError("40001"), maxTransactionRetries + 1, 0, maxTransactionRetries + 1},
+		{"does not retry other errors", []error{boom}, boom, 1, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			attempts := 0
+			err := NewUnitOfWork(db).Do(func(repos *Repositories) error {
+				if repos == nil || repos.Events == nil {
+					t.Fatal("Do passed no repositories")
+				}
+				err := tt.errs[len(tt.errs)-1]
+				if attempts < len(tt.errs) {
+					err = tt.errs[attempts]
+				}
+				attempts++
+				return err
+			})
+			if err != tt.want {
+				t.Errorf("Do returned %v, want %v", err, tt.want)
+			}
+			if attempts != tt.attempts || fake.begins != tt.attempts {
+				t.Errorf("Do ran %d attempts in %d transactions, want %d", attempts, fake.begins, tt.attempts)
+			}
+			if fake.commits != tt.commits || fake.rollbacks != tt.rollbacks {
+				t.Errorf("Do committed %d and rolled back %d times, want %d and %d", fake.commits, fake.rollbacks, tt.commits, tt.rollbacks)
+			}
+			if fake.isolation != driver.IsolationLevel(sql.LevelSerializable) {
+				t.Errorf("Do used isolation level %d, want serializable", fake.isolation)
+			}
+		})
+	}
+}
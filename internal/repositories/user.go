@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"time"
 	"together-backend/internal/models"
 
 	"gorm.io/gorm"
@@ -14,6 +15,7 @@ type UserRepo interface {
 	UpdateProfile(user *models.User, name string, address int) (*models.User, error)
 	UpdateProfileWithAvatar(user *models.User, name string, address int, avatarUrl string) (*models.User, error)
 	ChangePassword(user *models.User, hashPassword []byte) (*models.User, error)
+	SuspendUser(user *models.User, suspendedAt time.Time, until *time.Time) (*models.User, error)
 }
 
 type userDB struct {
@@ -63,28 +65,48 @@ func (userDB *userDB) GetUserDetail(id int) (*models.User, error) {
 }
 
 func (userDB *userDB) UpdateProfile(user *models.User, name string, address int) (*models.User, error) {
-	err := userDB.db.Model(user).Updates(map[string]interface{}{"name": name, "address": address}).Error
-	if err != nil {
-		return nil, err
+	version := user.Version
+	result := userDB.db.Model(user).Where("version = ?", version).Updates(map[string]interface{}{"name": name, "address": address, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrVersionConflict
 	}
+	user.Version = version + 1
 
-	return user, err
+	return user, nil
 }
 
 func (userDB *userDB) UpdateProfileWithAvatar(user *models.User, name string, address int, avatarUrl string) (*models.User, error) {
-	err := userDB.db.Model(user).Updates(map[string]interface{}{"name": name, "address": address, "avatar": avatarUrl}).Error
+	version := user.Version
+	result := userDB.db.Model(user).Where("version = ?", version).Updates(map[string]interface{}{"name": name, "address": address, "avatar": avatarUrl, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrVersionConflict
+	}
+	user.Version = version + 1
+
+	return user, nil
+}
+
+func (userDB *userDB) ChangePassword(user *models.User, hashPassword []byte) (*models.User, error) {
+	err := userDB.db.Model(user).Updates(map[string]interface{}{"password": hashPassword, "version": gorm.Expr("version + 1")}).Error
 	if err != nil {
 		return nil, err
 	}
+	user.Version++
 
 	return user, err
 }
 
-func (userDB *userDB) ChangePassword(user *models.User, hashPassword []byte) (*models.User, error) {
-	err := userDB.db.Model(user).Updates(map[string]interface{}{"password": hashPassword}).Error
+func (userDB *userDB) SuspendUser(user *models.User, suspendedAt time.Time, until *time.Time) (*models.User, error) {
+	err := userDB.db.Model(user).Updates(map[string]interface{}{"suspended_at": suspendedAt, "suspended_until": until}).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return user, err
+	return user, nil
 }
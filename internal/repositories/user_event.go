@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"fmt"
+	"time"
 	"together-backend/internal/models"
 
 	"gorm.io/gorm"
@@ -11,6 +13,20 @@ type UserEventRepo interface {
 	GetUserFromEvent(userId, eventId int) (*models.UserEvent, error)
 	AddUserToEvent(userId, eventId int) (*models.UserEvent, error)
 	RemoveUserFromEvent(userId, eventId int) (*models.UserEvent, error)
+	CheckIn(userId, eventId int, checkedInAt time.Time) (*models.UserEvent, error)
+	CountAttendance(eventId int) (int64, int64, error)
+	GetUserIdsByEventId(eventId int) ([]uint, error)
+	EachAttendee(eventId int, fn func(attendee *Attendee) error) error
+	GetAttendeesByHandles(eventId int, handles []string) ([]Attendee, error)
+}
+
+// Attendee is one row of an event roster.
+type Attendee struct {
+	UserId      uint
+	Name        string
+	Email       string
+	JoinedAt    *time.Time
+	CheckedInAt *time.Time
 }
 
 type userEventDB struct {
@@ -33,9 +49,11 @@ func (userEventDB *userEventDB) GetUserFromEvent(userId, eventId int) (*models.U
 }
 
 func (userEventDB *userEventDB) AddUserToEvent(userId, eventId int) (*models.UserEvent, error) {
+	joinedAt := time.Now()
 	var userEvent models.UserEvent = models.UserEvent{
-		UserId:  uint(userId),
-		EventId: uint(eventId),
+		UserId:   uint(userId),
+		EventId:  uint(eventId),
+		JoinedAt: &joinedAt,
 	}
 	if err := userEventDB.db.Create(&userEvent).Error; err != nil {
 		return nil, err
@@ -45,7 +63,6 @@ func (userEventDB *userEventDB) AddUserToEvent(userId, eventId int) (*models.Use
 }
 
 func (userEventDB *userEventDB) RemoveUserFromEvent(userId, eventId int) (*models.UserEvent, error) {
-
 	var userEvent models.UserEvent = models.UserEvent{
 		UserId:  uint(userId),
 		EventId: uint(eventId),
@@ -56,3 +73,96 @@ func (userEventDB *userEventDB) RemoveUserFromEvent(userId, eventId int) (*model
 
 	return &userEvent, nil
 }
+
+func (userEventDB *userEventDB) CheckIn(userId, eventId int, checkedInAt time.Time) (*models.UserEvent, error) {
+	result := userEventDB.db.Model(&models.UserEvent{}).
+		Where("user_id = ? AND event_id = ? AND checked_in_at IS NULL", userId, eventId).
+		Update("checked_in_at", checkedInAt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("user has already checked in")
+	}
+
+	return &models.UserEvent{
+		UserId:      uint(userId),
+		EventId:     uint(eventId),
+		CheckedInAt: &checkedInAt,
+	}, nil
+}
+
+func (userEventDB *userEventDB) CountAttendance(eventId int) (int64, int64, error) {
+	var attendance struct {
+		Attendees int64
+		CheckedIn int64
+	}
+	err := userEventDB.db.Model(&models.UserEvent{}).
+		Select("COUNT(*) AS attendees, COUNT(checked_in_at) AS checked_in").
+		Where("event_id = ?", eventId).
+		Scan(&attendance).Error
+	if err != nil {
+		return int64(0), int64(0), err
+	}
+
+	return attendance.Attendees, attendance.CheckedIn, nil
+}
+
+func (userEventDB *userEventDB) GetUserIdsByEventId(eventId int) ([]uint, error) {
+	var userIds []uint
+	err := userEventDB.db.Model(&models.UserEvent{}).
+		Where("event_id = ?", eventId).
+		Pluck("user_id", &userIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
+
+// EachAttendee walks the users of the event's Users association row by row
+// through a cursor, so large rosters are never loaded at once.
+func (userEventDB *userEventDB) EachAttendee(eventId int, fn func(attendee *Attendee) error) error {
+	rows, err := userEventDB.db.Model(&models.UserEvent{}).
+		Select("users.id AS user_id, users.name, users.email, user_events.joined_at, user_events.checked_in_at").
+		Joins("JOIN users ON users.id = user_events.user_id AND users.deleted_at IS NULL").
+		Where("user_events.event_id = ?", eventId).
+		Order("user_events.joined_at, users.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attendee Attendee
+		if err := userEventDB.db.ScanRows(rows, &attendee); err != nil {
+			return err
+		}
+		if err := fn(&attendee); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetAttendeesByHandles finds attendees whose email local part or name
+// without spaces matches one of the lower-cased handles.
+func (userEventDB *userEventDB) GetAttendeesByHandles(eventId int, handles []string) ([]Attendee, error) {
+	var attendees []Attendee
+	if len(handles) == 0 {
+		return attendees, nil
+	}
+
+	err := userEventDB.db.Model(&models.UserEvent{}).
+		Select("users.id AS user_id, users.name, users.email").
+		Joins("JOIN users ON users.id = user_events.user_id AND users.deleted_at IS NULL").
+		Where("user_events.event_id = ?", eventId).
+		Where("LOWER(SPLIT_PART(users.email, '@', 1)) IN ? OR LOWER(REPLACE(users.name, ' ', '')) IN ?", handles, handles).
+		Scan(&attendees).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attendees, nil
+}
@@ -18,19 +18,70 @@ func New() *mux.Router {
 	router.HandleFunc("/api/v1/logout", middleware.Auth(handlers.Logout)).Methods("POST")
 
 	router.HandleFunc("/api/v1/events", middleware.Auth(handlers.CreateEvent)).Methods("POST")
-	router.HandleFunc("/api/v1/events", handlers.GetEvents).Methods("GET")
-	router.HandleFunc("/api/v1/events/{event_id}", handlers.GetEventDetail).Methods("GET")
+	router.HandleFunc("/api/v1/events", middleware.OptionalAuth(handlers.GetEvents)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}", middleware.OptionalAuth(handlers.GetEventDetail)).Methods("GET")
 	router.HandleFunc("/api/v1/events/{event_id}", middleware.Auth(handlers.DeleteEvent)).Methods("DELETE")
 	router.HandleFunc("/api/v1/events/{event_id}", middleware.Auth(handlers.UpdateEvent)).Methods("PUT")
+	router.HandleFunc("/api/v1/events/{event_id}/history", middleware.OptionalAuth(handlers.GetEventHistory)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/gallery", middleware.OptionalAuth(handlers.GetEventGallery)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/duplicate", middleware.Auth(handlers.DuplicateEvent)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/status", middleware.Auth(handlers.ChangeEventStatus)).Methods("PUT")
 	router.HandleFunc("/api/v1/events/{event_id}/join", middleware.Auth(handlers.JoinEvent)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/ticket", middleware.Auth(handlers.GetEventTicket)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/check_in", middleware.Auth(handlers.CheckIn)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/attendees/export", middleware.Auth(handlers.ExportEventAttendees)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/reviews", middleware.OptionalAuth(handlers.GetEventReviews)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/reviews", middleware.Auth(handlers.CreateEventReview)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/reactions", middleware.Auth(handlers.ToggleEventReaction)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/reports", middleware.Auth(handlers.ReportEvent)).Methods("POST")
 
-	router.HandleFunc("/api/v1/events/{event_id}/comments", handlers.GetCommentsByEventId).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions", middleware.OptionalAuth(handlers.GetEventSessions)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions", middleware.Auth(handlers.CreateEventSession)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions/{session_id}", middleware.Auth(handlers.UpdateEventSession)).Methods("PUT")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions/{session_id}", middleware.Auth(handlers.DeleteEventSession)).Methods("DELETE")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions/{session_id}/signup", middleware.Auth(handlers.SignupEventSession)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/sessions/{session_id}/signup", middleware.Auth(handlers.CancelEventSessionSignup)).Methods("DELETE")
+
+	router.HandleFunc("/api/v1/events/{event_id}/polls", middleware.OptionalAuth(handlers.GetEventPolls)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/polls", middleware.Auth(handlers.CreateEventPoll)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/polls/{poll_id}", middleware.Auth(handlers.DeleteEventPoll)).Methods("DELETE")
+	router.HandleFunc("/api/v1/events/{event_id}/polls/{poll_id}/vote", middleware.Auth(handlers.VoteEventPoll)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/polls/{poll_id}/close", middleware.Auth(handlers.CloseEventPoll)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/polls/{poll_id}/apply", middleware.Auth(handlers.ApplyEventPoll)).Methods("POST")
+
+	router.HandleFunc("/api/v1/events/{event_id}/staff", middleware.Auth(handlers.GetEventStaff)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/staff", middleware.Auth(handlers.AddEventStaff)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/staff/{user_id}", middleware.Auth(handlers.RemoveEventStaff)).Methods("DELETE")
+	router.HandleFunc("/api/v1/events/{event_id}/transfer_ownership", middleware.Auth(handlers.TransferEventOwnership)).Methods("POST")
+
+	router.HandleFunc("/api/v1/events/{event_id}/comments", middleware.OptionalAuth(handlers.GetCommentsByEventId)).Methods("GET")
 	router.HandleFunc("/api/v1/events/{event_id}/comments", middleware.Auth(handlers.CreateComment)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}", middleware.Auth(handlers.EditComment)).Methods("PUT")
 	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}", middleware.Auth(handlers.DeleteComment)).Methods("DELETE")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}/history", middleware.Auth(handlers.GetCommentHistory)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}/moderation", middleware.Auth(handlers.ModerateComment)).Methods("PUT")
+	router.HandleFunc("/api/v1/events/{event_id}/moderation_log", middleware.Auth(handlers.GetCommentModerationLog)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}/replies", middleware.OptionalAuth(handlers.GetCommentReplies)).Methods("GET")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}/reactions", middleware.Auth(handlers.ToggleCommentReaction)).Methods("POST")
+	router.HandleFunc("/api/v1/events/{event_id}/comments/{comment_id}/reports", middleware.Auth(handlers.ReportComment)).Methods("POST")
 
 	router.HandleFunc("/api/v1/users/{user_id}", middleware.Auth(handlers.GetUserDetail)).Methods("GET")
 	router.HandleFunc("/api/v1/users/{user_id}", middleware.Auth(handlers.UpdateProfile)).Methods("PUT")
+	router.HandleFunc("/api/v1/users/{user_id}/schedule", middleware.Auth(handlers.GetUserSchedule)).Methods("GET")
 	router.HandleFunc("/api/v1/users/{user_id}/change_password", middleware.Auth(handlers.ChangePassword)).Methods("PUT")
+	router.HandleFunc("/api/v1/users/{user_id}/reports", middleware.Auth(handlers.ReportUser)).Methods("POST")
+
+	router.HandleFunc("/api/v1/moderation/items", middleware.Auth(handlers.GetModerationQueue)).Methods("GET")
+	router.HandleFunc("/api/v1/moderation/items/{item_id}", middleware.Auth(handlers.GetModerationItem)).Methods("GET")
+	router.HandleFunc("/api/v1/moderation/items/{item_id}/assignee", middleware.Auth(handlers.AssignModerationItem)).Methods("PUT")
+	router.HandleFunc("/api/v1/moderation/items/{item_id}/resolve", middleware.Auth(handlers.ResolveModerationItem)).Methods("POST")
+
+	router.HandleFunc("/api/v1/templates", middleware.Auth(handlers.GetEventTemplates)).Methods("GET")
+	router.HandleFunc("/api/v1/templates", middleware.Auth(handlers.CreateEventTemplate)).Methods("POST")
+	router.HandleFunc("/api/v1/templates/{template_id}", middleware.Auth(handlers.DeleteEventTemplate)).Methods("DELETE")
+
+	router.HandleFunc("/api/v1/notifications", middleware.Auth(handlers.GetNotifications)).Methods("GET")
+	router.HandleFunc("/api/v1/notifications/read", middleware.Auth(handlers.MarkNotificationsRead)).Methods("PUT")
 
 	return router
 }
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+	"together-backend/internal/database"
+	"together-backend/internal/repositories"
+	"together-backend/internal/usecases"
+)
+
+// StartEventPublisher publishes due draft events every interval. It runs once
+// right away, so drafts that fell due while the server was down are caught up.
+func StartEventPublisher(interval time.Duration) {
+	db := database.ConnectDB()
+	eventUsecase := usecases.NewEventUsecase(
+		repositories.NewEventRepo(db),
+		repositories.NewEventRoleRepo(db),
+		repositories.NewUserRepo(db),
+		repositories.NewImageRepo(db),
+		repositories.NewUserEventRepo(db),
+		repositories.NewNotificationRepo(db),
+		repositories.NewEventRevisionRepo(db),
+		repositories.NewEventReviewRepo(db),
+		repositories.NewEventSessionRepo(db),
+		repositories.NewReactionRepo(db),
+		repositories.NewModerationRepo(db),
+		usecases.NewContentModerator(),
+		repositories.NewUnitOfWork(db),
+	)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			published, err := eventUsecase.PublishDueEventsUsecase(time.Now())
+			if err != nil {
+				log.Printf("failed to publish due events: %s", err)
+			} else if published > 0 {
+				log.Printf("published %d scheduled events", published)
+			}
+			<-ticker.C
+		}
+	}()
+}
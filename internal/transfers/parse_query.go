@@ -0,0 +1,161 @@
+package transfers
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+func parseQueryTime(value string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02T15:04:05Z0700", value)
+	if err != nil {
+		return time.Parse("2006-01-02", value)
+	}
+	return t, nil
+}
+
+// ParseTimeRange reads the optional from and to query parameters.
+func ParseTimeRange(queries url.Values) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if queries.Get("from") != "" {
+		t, err := parseQueryTime(queries.Get("from"))
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+	if queries.Get("to") != "" {
+		t, err := parseQueryTime(queries.Get("to"))
+		if err != nil {
+			return nil, nil, err
+		}
+		to = &t
+	}
+	return from, to, nil
+}
+
+func ParseEventQuery(queries url.Values) (*repositories.EventQuery, error) {
+	var (
+		query repositories.EventQuery
+		err   error
+	)
+	query.Search = queries.Get("search")
+	query.Period = queries.Get("period")
+
+	if queries.Get("created_by") != "" {
+		query.CreatedBy, err = strconv.Atoi(queries.Get("created_by"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if queries.Get("attendee_id") != "" {
+		query.AttendeeId, err = strconv.Atoi(queries.Get("attendee_id"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	// legacy: user_id with type=created lists created events, otherwise joined events
+	if queries.Get("user_id") != "" {
+		userId, err := strconv.Atoi(queries.Get("user_id"))
+		if err != nil {
+			return nil, err
+		}
+		if queries.Get("type") == "created" {
+			query.CreatedBy = userId
+		} else {
+			query.AttendeeId = userId
+		}
+	}
+	if queries.Get("location") != "" {
+		query.Location, err = strconv.Atoi(queries.Get("location"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	query.From, query.To, err = ParseTimeRange(queries)
+	if err != nil {
+		return nil, err
+	}
+	if queries.Get("status") != "" {
+		for _, status := range strings.Split(queries.Get("status"), ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				query.Statuses = append(query.Statuses, status)
+			}
+		}
+	}
+	if queries.Get("sort") != "" {
+		query.Sort, err = repositories.ParseEventSort(queries.Get("sort"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &query, nil
+}
+
+// ParseModerationFilter reads the moderation queue filters. The queue shows
+// pending items unless status asks for another one, or "all". assignee is
+// a user id, "me" or "none".
+func ParseModerationFilter(queries url.Values, userId int) (*repositories.ModerationFilter, error) {
+	var (
+		filter repositories.ModerationFilter
+		err    error
+	)
+	filter.Status = queries.Get("status")
+	switch filter.Status {
+	case "":
+		filter.Status = models.ModerationStatusPending
+	case "all":
+		filter.Status = ""
+	}
+	filter.TargetType = queries.Get("target_type")
+	filter.Source = queries.Get("source")
+	filter.Category = queries.Get("category")
+
+	if queries.Get("event_id") != "" {
+		filter.EventId, err = strconv.Atoi(queries.Get("event_id"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch assignee := queries.Get("assignee"); assignee {
+	case "":
+	case "me":
+		filter.AssigneeId = userId
+	case "none":
+		filter.Unassigned = true
+	default:
+		filter.AssigneeId, err = strconv.Atoi(assignee)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &filter, nil
+}
+
+func ParsePagination(queries url.Values, pageKey string, defaultLimit int) (*repositories.Pagination, error) {
+	var (
+		pagination = repositories.Pagination{Page: 1, Limit: defaultLimit}
+		err        error
+	)
+	pagination.Cursor = queries.Get("cursor")
+
+	if queries.Get(pageKey) != "" {
+		pagination.Page, err = strconv.Atoi(queries.Get(pageKey))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if queries.Get("limit") != "" {
+		pagination.Limit, err = strconv.Atoi(queries.Get("limit"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &pagination, nil
+}
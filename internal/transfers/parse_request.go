@@ -7,30 +7,61 @@ import (
 	"together-backend/internal/usecases"
 )
 
+func formValue(multipartFrom *multipart.Form, key string) string {
+	if len(multipartFrom.Value[key]) == 0 {
+		return ""
+	}
+	return multipartFrom.Value[key][0]
+}
+
+// ParseRequestBodyFromMultipartFrom leaves missing fields empty, they may
+// be filled from the template given as template_id.
 func ParseRequestBodyFromMultipartFrom(multipartFrom *multipart.Form) (*usecases.ReqBodyEvent, error) {
 	var (
 		reqBody usecases.ReqBodyEvent
 		err     error
 	)
-	reqBody.Title = multipartFrom.Value["title"][0]
-	reqBody.Content = multipartFrom.Value["content"][0]
-	reqBody.CreatedBy, err = strconv.ParseUint(multipartFrom.Value["created_by"][0], 10, 64)
-	if err != nil {
-		return nil, err
+	reqBody.Title = formValue(multipartFrom, "title")
+	reqBody.Content = formValue(multipartFrom, "content")
+	if value := formValue(multipartFrom, "created_by"); value != "" {
+		reqBody.CreatedBy, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
 	}
-	reqBody.StartTime, err = time.Parse("2006-01-02T15:04:05Z0700", multipartFrom.Value["start_time"][0])
-	if err != nil {
-		return nil, err
+	if value := formValue(multipartFrom, "start_time"); value != "" {
+		reqBody.StartTime, err = time.Parse("2006-01-02T15:04:05Z0700", value)
+		if err != nil {
+			return nil, err
+		}
 	}
-	reqBody.EndTime, err = time.Parse("2006-01-02T15:04:05Z0700", multipartFrom.Value["end_time"][0])
-	if err != nil {
-		return nil, err
+	if value := formValue(multipartFrom, "end_time"); value != "" {
+		reqBody.EndTime, err = time.Parse("2006-01-02T15:04:05Z0700", value)
+		if err != nil {
+			return nil, err
+		}
 	}
-	reqBody.Location, err = strconv.Atoi(multipartFrom.Value["location"][0])
-	if err != nil {
-		return nil, err
+	if value := formValue(multipartFrom, "location"); value != "" {
+		reqBody.Location, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reqBody.DetailLocation = formValue(multipartFrom, "detail_location")
+	reqBody.Status = formValue(multipartFrom, "status")
+	if value := formValue(multipartFrom, "publish_at"); value != "" {
+		publishAt, err := time.Parse("2006-01-02T15:04:05Z0700", value)
+		if err != nil {
+			return nil, err
+		}
+		reqBody.PublishAt = &publishAt
+	}
+	if value := formValue(multipartFrom, "template_id"); value != "" {
+		reqBody.TemplateId, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
 	}
-	reqBody.DetailLocation = multipartFrom.Value["detail_location"][0]
 	return &reqBody, nil
 }
 
@@ -79,3 +110,18 @@ func ParseRequestUpdateProfile(multipartFrom *multipart.Form) (*usecases.ReqBody
 
 	return &reqBody, nil
 }
+
+// ParseRequestComment reads a comment sent as multipart, the way comments
+// with images arrive.
+func ParseRequestComment(multipartFrom *multipart.Form) (*usecases.ReqBodyComment, error) {
+	var reqBody usecases.ReqBodyComment
+	reqBody.Content = formValue(multipartFrom, "content")
+	if value := formValue(multipartFrom, "parent_id"); value != "" {
+		parentId, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		reqBody.ParentId = uint(parentId)
+	}
+	return &reqBody, nil
+}
@@ -54,6 +54,9 @@ func (uc *accountUsecase) Login(email, password string) (*AuthResponse, error) {
 	if err := bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil {
 		return nil, err
 	}
+	if user.Suspended(time.Now()) {
+		return nil, fmt.Errorf("your account is suspended")
+	}
 
 	// Generate token
 	claims := &Claims{
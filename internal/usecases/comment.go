@@ -2,42 +2,101 @@ package usecases
 
 import (
 	"fmt"
+	"log"
+	"mime/multipart"
+	"time"
 	"together-backend/internal/models"
 	"together-backend/internal/repositories"
+	"unicode/utf8"
 )
 
 type CommentCase interface {
-	GetCommentsByEventIdUsecase(eventId, page, size int) ([]models.Comment, int64, error)
-	CreateCommentUsecase(reqBody *ReqBodyComment, eventId, userId int) (*models.Comment, error)
-	DeleteCommentUsecase(commentId, eventId, userId int) (*models.Comment, error)
+	GetCommentsByEventIdUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.Comment, *repositories.PageInfo, int64, error)
+	CreateCommentUsecase(reqBody *ReqBodyComment, eventId, userId int, images []*multipart.FileHeader) (*models.Comment, error)
+	DeleteCommentUsecase(commentId, eventId, userId int, reason string) (*models.Comment, error)
+	GetRepliesUsecase(commentId, eventId, viewerId int, pagination *repositories.Pagination) ([]models.Comment, *repositories.PageInfo, error)
+	EditCommentUsecase(reqBody *ReqBodyComment, commentId, eventId, userId int) (*models.Comment, error)
+	GetCommentHistoryUsecase(commentId, eventId, userId int) ([]models.CommentRevision, error)
+	ToggleCommentReactionUsecase(reqBody *ReqBodyReaction, commentId, eventId, userId int) ([]models.ReactionCount, bool, error)
+	ModerateCommentUsecase(reqBody *ReqBodyModerateComment, commentId, eventId, userId int) (*models.Comment, error)
+	GetModerationLogUsecase(eventId, userId int, pagination *repositories.Pagination) ([]models.CommentModerationLog, *repositories.PageInfo, error)
 }
 
+// previewReplies is how many replies come with each comment in a list;
+// the rest of a thread is paged through GetRepliesUsecase.
+const previewReplies = 3
+
 type commentUsecase struct {
-	commentRepo   repositories.CommentRepo
-	userEventRepo repositories.UserEventRepo
+	eventAuthorizer
+	commentRepo      repositories.CommentRepo
+	userEventRepo    repositories.UserEventRepo
+	reactionRepo     repositories.ReactionRepo
+	notificationRepo repositories.NotificationRepo
+	userRepo         repositories.UserRepo
+	moderationRepo   repositories.ModerationRepo
+	moderator        *ContentModerator
+	uploadUsecase    UploadUseCase
+	uow              repositories.UnitOfWork
 }
 
 type ReqBodyComment struct {
-	Content string
+	Content  string
+	ParentId uint `json:"parent_id"`
 }
 
-func NewCommentUsecase(commentRepo repositories.CommentRepo, userEventRepo repositories.UserEventRepo) CommentCase {
+func NewCommentUsecase(commentRepo repositories.CommentRepo, userEventRepo repositories.UserEventRepo, eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo, reactionRepo repositories.ReactionRepo, notificationRepo repositories.NotificationRepo, userRepo repositories.UserRepo, moderationRepo repositories.ModerationRepo, moderator *ContentModerator, uploadUsecase UploadUseCase, uow repositories.UnitOfWork) CommentCase {
 	return &commentUsecase{
-		commentRepo:   commentRepo,
-		userEventRepo: userEventRepo,
+		eventAuthorizer:  eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		commentRepo:      commentRepo,
+		userEventRepo:    userEventRepo,
+		reactionRepo:     reactionRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		moderationRepo:   moderationRepo,
+		moderator:        moderator,
+		uploadUsecase:    uploadUsecase,
+		uow:              uow,
 	}
 }
 
-func (uc *commentUsecase) CreateCommentUsecase(reqBody *ReqBodyComment, eventId, userId int) (*models.Comment, error) {
+func (uc *commentUsecase) inTransaction(fn func(tx *commentUsecase) error) error {
+	return inTransaction(uc.uow, uc, func(tx transactional) error {
+		return fn(tx.(*commentUsecase))
+	})
+}
+
+func (uc *commentUsecase) withRepositories(repos *repositories.Repositories) transactional {
+	return &commentUsecase{
+		eventAuthorizer:  eventAuthorizer{eventRepo: repos.Events, eventRoleRepo: repos.EventRoles},
+		commentRepo:      repos.Comments,
+		userEventRepo:    repos.UserEvents,
+		reactionRepo:     repos.Reactions,
+		notificationRepo: repos.Notifications,
+		userRepo:         repos.Users,
+		moderationRepo:   repos.Moderation,
+		moderator:        uc.moderator,
+		uploadUsecase:    uc.uploadUsecase,
+	}
+}
+
+func (uc *commentUsecase) CreateCommentUsecase(reqBody *ReqBodyComment, eventId, userId int, images []*multipart.FileHeader) (*models.Comment, error) {
 	if eventId <= 0 {
 		return nil, fmt.Errorf("invalid event id")
 	}
 	if userId <= 0 {
 		return nil, fmt.Errorf("invalid user id")
 	}
-	if reqBody.Content == "" {
+	// a comment may be just photos
+	if reqBody.Content == "" && len(images) == 0 {
 		return nil, fmt.Errorf("content of comment is empty")
 	}
+	if utf8.RuneCountInString(reqBody.Content) > models.MaxCommentLength {
+		return nil, fmt.Errorf("content of comment must be at most %d characters", models.MaxCommentLength)
+	}
+
+	if _, err := uc.visibleEvent(eventId, userId); err != nil {
+		return nil, err
+	}
 
 	userEvent, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
 	if err != nil && err.Error() != "record not found" {
@@ -48,34 +107,174 @@ func (uc *commentUsecase) CreateCommentUsecase(reqBody *ReqBodyComment, eventId,
 		return nil, fmt.Errorf("you haven't joined in the event yet")
 	}
 
-	newComment, err := uc.commentRepo.CreateComment(userId, eventId, reqBody.Content)
+	// replies stay one level deep: answering a reply joins its thread
+	var parentId *uint
+	if reqBody.ParentId != 0 {
+		parent, err := uc.commentRepo.GetEventComment(int(reqBody.ParentId), eventId)
+		if err != nil {
+			if err.Error() == "record not found" {
+				return nil, fmt.Errorf("parent comment not found")
+			}
+			return nil, err
+		}
+		if parent.HiddenAt != nil {
+			return nil, fmt.Errorf("parent comment not found")
+		}
+		parentId = &parent.Id
+		if parent.ParentId != nil {
+			parentId = parent.ParentId
+		}
+	}
+
+	verdict, err := uc.moderateComment(userId, true, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := uc.resolveMentions(eventId, reqBody.Content)
 	if err != nil {
 		return nil, err
 	}
 
+	// upload last, so a rejected comment leaves no stray images behind
+	var imageUrls []string
+	if len(images) > 0 {
+		imageUrls, err = uc.uploadUsecase.CommentImageUpload(images)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a held comment is hidden in the same transaction that creates it, so
+	// it is never public
+	var newComment *models.Comment
+	err = uc.inTransaction(func(tx *commentUsecase) error {
+		newComment, err = tx.commentRepo.CreateComment(userId, eventId, parentId, reqBody.Content, mentions, imageUrls)
+		if err != nil {
+			return err
+		}
+		if verdict.Action == models.ModerationQueue {
+			return tx.holdComment(newComment, verdict.Reason)
+		}
+		return nil
+	})
+	if err != nil {
+		uc.discardImages(imageUrls)
+		return nil, err
+	}
+	// held comments notify nobody until a moderator lets them through
+	if verdict.Action == models.ModerationQueue {
+		return newComment, nil
+	}
+	if err := uc.notifyMentions(newComment, nil); err != nil {
+		return nil, err
+	}
+
 	return newComment, nil
 }
 
-func (uc *commentUsecase) GetCommentsByEventIdUsecase(eventId, page, size int) ([]models.Comment, int64, error) {
+// discardImages removes the files of images no comment holds. A failure
+// leaves stray files behind but does not fail the request.
+func (uc *commentUsecase) discardImages(imageUrls []string) {
+	if err := uc.uploadUsecase.DeleteImages(imageUrls); err != nil {
+		log.Printf("failed to delete comment images: %s", err)
+	}
+}
+
+func (uc *commentUsecase) GetCommentsByEventIdUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.Comment, *repositories.PageInfo, int64, error) {
 
 	if eventId <= 0 {
-		return nil, int64(0), fmt.Errorf("invalid event id")
+		return nil, nil, int64(0), fmt.Errorf("invalid event id")
+	}
+	pagination.Normalize()
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, nil, int64(0), err
 	}
 
 	total, err := uc.commentRepo.CountCommentsByEventId(eventId)
 	if err != nil {
-		return nil, int64(0), err
+		return nil, nil, int64(0), err
+	}
+
+	comments, pageInfo, err := uc.commentRepo.GetCommentsByEventId(eventId, pagination)
+	if err != nil {
+		return nil, nil, int64(0), err
 	}
 
-	comments, err := uc.commentRepo.GetCommentsByEventId(eventId, size, page)
+	// pinned comments lead the first page and are left out of the others
+	if pagination.Cursor == "" && pagination.Page == 1 {
+		pinned, err := uc.commentRepo.GetPinnedComments(eventId)
+		if err != nil {
+			return nil, nil, int64(0), err
+		}
+		comments = append(pinned, comments...)
+	}
+
+	var parentIds []uint
+	for _, comment := range comments {
+		if comment.ReplyCount > 0 {
+			parentIds = append(parentIds, comment.Id)
+		}
+	}
+	replies, err := uc.commentRepo.GetFirstReplies(parentIds, previewReplies)
 	if err != nil {
-		return nil, int64(0), err
+		return nil, nil, int64(0), err
+	}
+	byParent := make(map[uint][]models.Comment)
+	for _, reply := range replies {
+		byParent[*reply.ParentId] = append(byParent[*reply.ParentId], reply)
+	}
+	for i := range comments {
+		comments[i].Replies = byParent[comments[i].Id]
 	}
 
-	return comments, total, nil
+	if err := uc.attachReactions(comments, viewerId); err != nil {
+		return nil, nil, int64(0), err
+	}
+
+	return comments, pageInfo, total, nil
 }
 
-func (uc *commentUsecase) DeleteCommentUsecase(commentId, eventId, userId int) (*models.Comment, error) {
+func (uc *commentUsecase) GetRepliesUsecase(commentId, eventId, viewerId int, pagination *repositories.Pagination) ([]models.Comment, *repositories.PageInfo, error) {
+	if eventId <= 0 {
+		return nil, nil, fmt.Errorf("invalid event id")
+	}
+	if commentId <= 0 {
+		return nil, nil, fmt.Errorf("invalid comment id")
+	}
+	pagination.Normalize()
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, nil, err
+	}
+
+	comment, err := uc.commentRepo.GetEventComment(commentId, eventId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if comment.HiddenAt != nil {
+		return nil, nil, fmt.Errorf("record not found")
+	}
+	if comment.ParentId != nil {
+		return nil, nil, fmt.Errorf("comment is a reply, load its thread instead")
+	}
+
+	replies, pageInfo, err := uc.commentRepo.GetReplies(commentId, pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.attachReactions(replies, viewerId); err != nil {
+		return nil, nil, err
+	}
+
+	return replies, pageInfo, nil
+}
+
+// DeleteCommentUsecase lets authors delete their comments. Event staff and
+// site moderators can delete any comment of the event, giving a reason that
+// goes to the moderation log.
+func (uc *commentUsecase) DeleteCommentUsecase(commentId, eventId, userId int, reason string) (*models.Comment, error) {
 	if eventId <= 0 {
 		return nil, fmt.Errorf("invalid event id")
 	}
@@ -86,24 +285,174 @@ func (uc *commentUsecase) DeleteCommentUsecase(commentId, eventId, userId int) (
 		return nil, fmt.Errorf("invalid comment id")
 	}
 
-	userEvent, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
-	if err != nil && err.Error() != "record not found" {
+	comment, err := uc.commentRepo.GetEventComment(commentId, eventId)
+	if err != nil {
 		return nil, err
 	}
 
-	if userEvent == nil {
-		return nil, fmt.Errorf("you haven't joined in the event yet")
+	var log *models.CommentModerationLog
+	if comment.UserId != uint(userId) {
+		if err := uc.authorizeModeration(eventId, userId); err != nil {
+			return nil, err
+		}
+		log, err = moderationLog(comment, userId, models.CommentActionDelete, reason)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deleteComment, imageUrls, err := uc.commentRepo.DeleteComment(comment, log)
+	if err != nil {
+		return nil, err
+	}
+	uc.discardImages(imageUrls)
+
+	return deleteComment, nil
+}
+
+func (uc *commentUsecase) EditCommentUsecase(reqBody *ReqBodyComment, commentId, eventId, userId int) (*models.Comment, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if commentId <= 0 {
+		return nil, fmt.Errorf("invalid comment id")
+	}
+	if reqBody.Content == "" {
+		return nil, fmt.Errorf("content of comment is empty")
+	}
+	if utf8.RuneCountInString(reqBody.Content) > models.MaxCommentLength {
+		return nil, fmt.Errorf("content of comment must be at most %d characters", models.MaxCommentLength)
 	}
 
 	comment, err := uc.commentRepo.GetComment(commentId, userId, eventId)
 	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, fmt.Errorf("you can only edit your own comments")
+		}
 		return nil, err
 	}
+	if comment.Content == reqBody.Content {
+		return comment, nil
+	}
 
-	deleteComment, err := uc.commentRepo.DeleteComment(comment)
+	verdict, err := uc.moderateComment(userId, false, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	return deleteComment, nil
+	mentions, err := uc.resolveMentions(eventId, reqBody.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := comment.Mentions
+	// flagged content is held in the same transaction that saves it, so it
+	// is never public
+	err = uc.inTransaction(func(tx *commentUsecase) error {
+		comment, err = tx.commentRepo.UpdateComment(comment, reqBody.Content, mentions, userId, time.Now())
+		if err != nil {
+			return err
+		}
+		if verdict.Action == models.ModerationQueue && comment.HiddenAt == nil {
+			return tx.holdComment(comment, verdict.Reason)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Action == models.ModerationQueue {
+		return comment, nil
+	}
+	// a hidden comment notifies nobody, as if it was held
+	if comment.HiddenAt != nil {
+		return comment, nil
+	}
+	if err := uc.notifyMentions(comment, previous); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetCommentHistoryUsecase lists prior contents of a comment, newest first.
+// Only event staff may read it.
+func (uc *commentUsecase) GetCommentHistoryUsecase(commentId, eventId, userId int) ([]models.CommentRevision, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if commentId <= 0 {
+		return nil, fmt.Errorf("invalid comment id")
+	}
+
+	if err := uc.authorizeModeration(eventId, userId); err != nil {
+		return nil, err
+	}
+	if _, err := uc.commentRepo.GetEventComment(commentId, eventId); err != nil {
+		return nil, err
+	}
+
+	return uc.commentRepo.GetCommentRevisions(commentId)
+}
+
+func (uc *commentUsecase) ToggleCommentReactionUsecase(reqBody *ReqBodyReaction, commentId, eventId, userId int) ([]models.ReactionCount, bool, error) {
+	if eventId <= 0 {
+		return nil, false, fmt.Errorf("invalid event id")
+	}
+	if commentId <= 0 {
+		return nil, false, fmt.Errorf("invalid comment id")
+	}
+	if !models.IsReactionEmoji(reqBody.Emoji) {
+		return nil, false, fmt.Errorf("unsupported reaction %q", reqBody.Emoji)
+	}
+
+	if _, err := uc.visibleEvent(eventId, userId); err != nil {
+		return nil, false, err
+	}
+
+	comment, err := uc.commentRepo.GetEventComment(commentId, eventId)
+	if err != nil {
+		return nil, false, err
+	}
+	if comment.HiddenAt != nil {
+		return nil, false, fmt.Errorf("record not found")
+	}
+
+	added, err := uc.reactionRepo.ToggleReaction(models.ReactionTargetComment, comment.Id, userId, reqBody.Emoji)
+	if err != nil {
+		return nil, false, err
+	}
+
+	counts, err := uc.reactionRepo.GetReactionCounts(models.ReactionTargetComment, []uint{comment.Id}, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	return reactionCounts(counts[comment.Id]), added, nil
+}
+
+// attachReactions loads the reactions of comments and their preview
+// replies with a single query.
+func (uc *commentUsecase) attachReactions(comments []models.Comment, viewerId int) error {
+	var ids []uint
+	for _, comment := range comments {
+		ids = append(ids, comment.Id)
+		for _, reply := range comment.Replies {
+			ids = append(ids, reply.Id)
+		}
+	}
+	counts, err := uc.reactionRepo.GetReactionCounts(models.ReactionTargetComment, ids, viewerId)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].Reactions = reactionCounts(counts[comments[i].Id])
+		for j := range comments[i].Replies {
+			comments[i].Replies[j].Reactions = reactionCounts(counts[comments[i].Replies[j].Id])
+		}
+	}
+	return nil
 }
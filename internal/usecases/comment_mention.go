@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"together-backend/internal/models"
+	"unicode/utf8"
+)
+
+// mentionPattern matches @handle when the @ does not follow a word
+// character, so email addresses in a comment are not taken as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])(@([\p{L}\p{N}_.\-]+))`)
+
+// maxMentions bounds how many distinct users one comment can notify.
+const maxMentions = 10
+
+type mentionToken struct {
+	Handle string
+	Offset int
+	Length int
+}
+
+// parseMentions finds the @handles in content. Offsets and lengths count
+// characters and cover the @ sign.
+func parseMentions(content string) []mentionToken {
+	var tokens []mentionToken
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[5]
+		// a mention at the end of a sentence keeps its punctuation outside
+		end = start + 1 + len(strings.TrimRight(content[start+1:end], ".-"))
+		if end == start+1 {
+			continue
+		}
+		tokens = append(tokens, mentionToken{
+			Handle: strings.ToLower(content[start+1 : end]),
+			Offset: utf8.RuneCountInString(content[:start]),
+			Length: utf8.RuneCountInString(content[start:end]),
+		})
+	}
+	return tokens
+}
+
+// resolveMentions matches the @handles in content against the attendees of
+// the event. A handle is an attendee's email local part, or their name with
+// spaces removed when no other attendee shares it. Unknown and ambiguous
+// handles stay plain text.
+func (uc *commentUsecase) resolveMentions(eventId int, content string) ([]models.CommentMention, error) {
+	tokens := parseMentions(content)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	var handles []string
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if !seen[token.Handle] {
+			seen[token.Handle] = true
+			handles = append(handles, token.Handle)
+		}
+	}
+
+	attendees, err := uc.userEventRepo.GetAttendeesByHandles(eventId, handles)
+	if err != nil {
+		return nil, err
+	}
+
+	byEmail := make(map[string]models.CommentMention)
+	byName := make(map[string][]models.CommentMention)
+	for _, attendee := range attendees {
+		mention := models.CommentMention{UserId: attendee.UserId, Name: attendee.Name}
+		local, _, _ := strings.Cut(attendee.Email, "@")
+		byEmail[strings.ToLower(local)] = mention
+		name := strings.ToLower(strings.ReplaceAll(attendee.Name, " ", ""))
+		byName[name] = append(byName[name], mention)
+	}
+
+	var mentions []models.CommentMention
+	users := make(map[uint]bool)
+	for _, token := range tokens {
+		mention, ok := byEmail[token.Handle]
+		if !ok && len(byName[token.Handle]) == 1 {
+			mention, ok = byName[token.Handle][0], true
+		}
+		if !ok {
+			continue
+		}
+		if !users[mention.UserId] && len(users) == maxMentions {
+			continue
+		}
+		users[mention.UserId] = true
+		mention.Offset = token.Offset
+		mention.Length = token.Length
+		mentions = append(mentions, mention)
+	}
+	return mentions, nil
+}
+
+// notifyMentions tells each user mentioned in comment, except its author and
+// the users already mentioned before an edit, that they were mentioned.
+func (uc *commentUsecase) notifyMentions(comment *models.Comment, previous []models.CommentMention) error {
+	if len(comment.Mentions) == 0 {
+		return nil
+	}
+
+	notified := map[uint]bool{comment.UserId: true}
+	for _, mention := range previous {
+		notified[mention.UserId] = true
+	}
+
+	event, err := uc.eventRepo.GetEventDetail(int(comment.EventId))
+	if err != nil {
+		return err
+	}
+
+	var notifications []models.Notification
+	for _, mention := range comment.Mentions {
+		if notified[mention.UserId] {
+			continue
+		}
+		notified[mention.UserId] = true
+		notifications = append(notifications, models.Notification{
+			UserId:  mention.UserId,
+			EventId: event.Id,
+			Type:    models.NotificationMention,
+			Message: fmt.Sprintf("%s mentioned you in a comment on event \"%s\"", comment.User.Name, event.Title),
+		})
+	}
+	return uc.notificationRepo.CreateNotifications(notifications)
+}
@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// maxModerationReason bounds the reason kept in the moderation log.
+const maxModerationReason = 500
+
+type ReqBodyModerateComment struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+}
+
+// authorizeModeration lets event staff and site moderators moderate the
+// comments of an event.
+func (uc *commentUsecase) authorizeModeration(eventId, userId int) error {
+	user, err := uc.userRepo.GetUserById(int64(userId))
+	if err != nil {
+		return err
+	}
+	if user.Role == models.UserRoleModerator {
+		_, err := uc.eventRepo.GetEventDetail(eventId)
+		return err
+	}
+
+	_, _, err = uc.authorize(eventId, userId, models.EventRoleModerator)
+	return err
+}
+
+// moderationLog builds the log entry of an action. Removing content needs a
+// reason, restoring it and pinning do not.
+func moderationLog(comment *models.Comment, userId int, action, reason string) (*models.CommentModerationLog, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" && (action == models.CommentActionHide || action == models.CommentActionDelete) {
+		return nil, fmt.Errorf("a reason is required to %s a comment", action)
+	}
+	if len([]rune(reason)) > maxModerationReason {
+		return nil, fmt.Errorf("reason must be at most %d characters", maxModerationReason)
+	}
+
+	return &models.CommentModerationLog{
+		EventId:   comment.EventId,
+		CommentId: comment.Id,
+		ActorId:   uint(userId),
+		Action:    action,
+		Reason:    reason,
+		Content:   comment.Content,
+	}, nil
+}
+
+func (uc *commentUsecase) ModerateCommentUsecase(reqBody *ReqBodyModerateComment, commentId, eventId, userId int) (*models.Comment, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if commentId <= 0 {
+		return nil, fmt.Errorf("invalid comment id")
+	}
+
+	if err := uc.authorizeModeration(eventId, userId); err != nil {
+		return nil, err
+	}
+	comment, err := uc.commentRepo.GetEventComment(commentId, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var updates map[string]interface{}
+	switch reqBody.Action {
+	case models.CommentActionHide:
+		if comment.HiddenAt != nil {
+			return nil, fmt.Errorf("comment is already hidden")
+		}
+		// a hidden comment can't stay pinned
+		updates = map[string]interface{}{"hidden_at": now, "pinned_at": nil}
+	case models.CommentActionUnhide:
+		if comment.HiddenAt == nil {
+			return nil, fmt.Errorf("comment is not hidden")
+		}
+		updates = map[string]interface{}{"hidden_at": nil}
+	case models.CommentActionPin:
+		if comment.ParentId != nil {
+			return nil, fmt.Errorf("replies can't be pinned")
+		}
+		if comment.HiddenAt != nil {
+			return nil, fmt.Errorf("hidden comments can't be pinned")
+		}
+		if comment.PinnedAt != nil {
+			return nil, fmt.Errorf("comment is already pinned")
+		}
+		pinned, err := uc.commentRepo.CountPinnedComments(eventId)
+		if err != nil {
+			return nil, err
+		}
+		if pinned >= models.MaxPinnedComments {
+			return nil, fmt.Errorf("an event can pin at most %d comments", models.MaxPinnedComments)
+		}
+		updates = map[string]interface{}{"pinned_at": now}
+	case models.CommentActionUnpin:
+		if comment.PinnedAt == nil {
+			return nil, fmt.Errorf("comment is not pinned")
+		}
+		updates = map[string]interface{}{"pinned_at": nil}
+	default:
+		return nil, fmt.Errorf("unknown moderation action %q", reqBody.Action)
+	}
+
+	log, err := moderationLog(comment, userId, reqBody.Action, reqBody.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.commentRepo.ModerateComment(comment, updates, log)
+}
+
+// moderateComment runs the comment through the content filters, masking
+// its content in place when they ask to.
+func (uc *commentUsecase) moderateComment(userId int, create bool, reqBody *ReqBodyComment) (*ModerationVerdict, error) {
+	verdict, err := uc.moderator.Moderate(&ModerationInput{
+		UserId:     userId,
+		TargetType: models.ModerationTargetComment,
+		Create:     create,
+		Fields:     []*string{&reqBody.Content},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Action == models.ModerationReject {
+		return nil, fmt.Errorf("comment was rejected: %s", verdict.Reason)
+	}
+	return verdict, nil
+}
+
+// holdComment hides the comment until a moderator reviews it.
+func (uc *commentUsecase) holdComment(comment *models.Comment, reason string) error {
+	now := time.Now()
+	_, err := uc.moderationRepo.HoldContent(&models.ModerationItem{
+		TargetType: models.ModerationTargetComment,
+		TargetId:   comment.Id,
+		EventId:    &comment.EventId,
+		AuthorId:   comment.UserId,
+		Source:     models.ModerationSourceFilter,
+		Reason:     reason,
+	}, now)
+	if err != nil {
+		return err
+	}
+	comment.HiddenAt = &now
+	return nil
+}
+
+// GetModerationLogUsecase lists the moderation actions of an event, newest
+// first.
+func (uc *commentUsecase) GetModerationLogUsecase(eventId, userId int, pagination *repositories.Pagination) ([]models.CommentModerationLog, *repositories.PageInfo, error) {
+	if eventId <= 0 {
+		return nil, nil, fmt.Errorf("invalid event id")
+	}
+	pagination.Normalize()
+
+	if err := uc.authorizeModeration(eventId, userId); err != nil {
+		return nil, nil, err
+	}
+
+	return uc.commentRepo.GetModerationLogs(eventId, pagination)
+}
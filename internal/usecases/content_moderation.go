@@ -0,0 +1,334 @@
+package usecases
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+	"together-backend/pkg"
+	"unicode"
+)
+
+// ModerationInput is user text about to be stored.
+type ModerationInput struct {
+	UserId     int
+	TargetType string
+	// Create is set for new content, posting rates only count new content.
+	Create bool
+	// Fields point at the texts to check. Masking rewrites them in place.
+	Fields []*string
+}
+
+type ModerationVerdict struct {
+	Action string
+	Reason string
+}
+
+// ContentFilter is one step of the moderation pipeline. It returns nil when
+// it has nothing against the input.
+type ContentFilter interface {
+	Check(input *ModerationInput) (*ModerationVerdict, error)
+}
+
+// ContentModerator runs user text through its filters in order and settles
+// on the strictest verdict.
+type ContentModerator struct {
+	filters []ContentFilter
+}
+
+func NewContentModerator(filters ...ContentFilter) *ContentModerator {
+	return &ContentModerator{
+		filters: filters,
+	}
+}
+
+// NewContentModeratorFromEnv builds the default pipeline of suspension,
+// word list, link spam and posting rate filters from the MODERATION_*
+// variables.
+func NewContentModeratorFromEnv(commentRepo repositories.CommentRepo, eventRepo repositories.EventRepo, userRepo repositories.UserRepo) *ContentModerator {
+	filters := []ContentFilter{NewSuspensionFilter(userRepo)}
+	if words := os.Getenv("MODERATION_BLOCKED_WORDS"); words != "" {
+		filters = append(filters, NewWordListFilter(strings.Split(words, ","), envAction("MODERATION_BLOCKED_WORDS_ACTION", models.ModerationMask)))
+	}
+	filters = append(filters,
+		NewLinkSpamFilter(envInt("MODERATION_MAX_LINKS", 3), envAction("MODERATION_LINK_SPAM_ACTION", models.ModerationQueue)),
+		NewPostingRateFilter(envInt("MODERATION_POST_LIMIT", 10), envDuration("MODERATION_POST_WINDOW", time.Minute), commentRepo, eventRepo),
+	)
+	return NewContentModerator(filters...)
+}
+
+func envAction(key, fallback string) string {
+	action := os.Getenv(key)
+	if _, ok := models.ModerationActionRanks[action]; !ok || action == models.ModerationAllow {
+		return fallback
+	}
+	return action
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func (m *ContentModerator) Moderate(input *ModerationInput) (*ModerationVerdict, error) {
+	verdict := &ModerationVerdict{Action: models.ModerationAllow}
+	var reasons []string
+	for _, filter := range m.filters {
+		result, err := filter.Check(input)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			continue
+		}
+		if models.ModerationActionRanks[result.Action] > models.ModerationActionRanks[verdict.Action] {
+			verdict.Action = result.Action
+		}
+		reasons = append(reasons, result.Reason)
+		if verdict.Action == models.ModerationReject {
+			break
+		}
+	}
+	verdict.Reason = strings.Join(reasons, "; ")
+	return verdict, nil
+}
+
+// wordSpan is a word of a text, folded for comparison, and the runes it
+// covers in the original text.
+type wordSpan struct {
+	word       string
+	start, end int
+}
+
+func foldWords(text string) []wordSpan {
+	var (
+		spans []wordSpan
+		word  []rune
+		start int
+	)
+	runes := []rune(text)
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || unicode.Is(unicode.Mn, runes[i])) {
+			if len(word) == 0 {
+				start = i
+			}
+			if f := pkg.FoldRune(runes[i]); f != 0 {
+				word = append(word, f)
+			}
+			continue
+		}
+		if len(word) > 0 {
+			spans = append(spans, wordSpan{word: string(word), start: start, end: i})
+			word = word[:0]
+		}
+	}
+	return spans
+}
+
+// wordListFilter matches blocked words and phrases regardless of case and
+// Vietnamese diacritics, so "Đồ ngốc" is caught by "do ngoc".
+type wordListFilter struct {
+	terms  [][]string
+	action string
+}
+
+func NewWordListFilter(words []string, action string) ContentFilter {
+	var terms [][]string
+	for _, word := range words {
+		var term []string
+		for _, span := range foldWords(word) {
+			term = append(term, span.word)
+		}
+		if len(term) > 0 {
+			terms = append(terms, term)
+		}
+	}
+	return &wordListFilter{terms: terms, action: action}
+}
+
+func (f *wordListFilter) Check(input *ModerationInput) (*ModerationVerdict, error) {
+	found := false
+	for _, field := range input.Fields {
+		spans := foldWords(*field)
+		var matched []wordSpan
+		for i := range spans {
+			for _, term := range f.terms {
+				if i+len(term) > len(spans) {
+					continue
+				}
+				match := true
+				for j, word := range term {
+					if spans[i+j].word != word {
+						match = false
+						break
+					}
+				}
+				if match {
+					matched = append(matched, spans[i:i+len(term)]...)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		found = true
+		if f.action == models.ModerationMask {
+			runes := []rune(*field)
+			masked := make([]bool, len(runes))
+			for _, span := range matched {
+				for i := span.start; i < span.end; i++ {
+					masked[i] = true
+				}
+			}
+			var out []rune
+			for i, r := range runes {
+				switch {
+				case !masked[i]:
+					out = append(out, r)
+				case !unicode.Is(unicode.Mn, r):
+					out = append(out, '*')
+				}
+			}
+			*field = string(out)
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return &ModerationVerdict{Action: f.action, Reason: "contains blocked words"}, nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()]+`)
+
+// linkShorteners hide where a link leads, a common trait of spam.
+var linkShorteners = map[string]bool{
+	"bit.ly":      true,
+	"tinyurl.com": true,
+	"goo.gl":      true,
+	"t.co":        true,
+	"ow.ly":       true,
+	"is.gd":       true,
+	"cutt.ly":     true,
+	"shorturl.at": true,
+}
+
+// linkSpamFilter flags text with more links than maxLinks or with links
+// through URL shorteners.
+type linkSpamFilter struct {
+	maxLinks int
+	action   string
+}
+
+func NewLinkSpamFilter(maxLinks int, action string) ContentFilter {
+	return &linkSpamFilter{maxLinks: maxLinks, action: action}
+}
+
+func (f *linkSpamFilter) Check(input *ModerationInput) (*ModerationVerdict, error) {
+	links := 0
+	shortened := false
+	for _, field := range input.Fields {
+		for _, link := range linkPattern.FindAllString(*field, -1) {
+			links++
+			host := strings.ToLower(link)
+			if i := strings.Index(host, "://"); i >= 0 {
+				host = host[i+3:]
+			}
+			if i := strings.IndexAny(host, "/?#:"); i >= 0 {
+				host = host[:i]
+			}
+			if linkShorteners[strings.TrimPrefix(host, "www.")] {
+				shortened = true
+			}
+		}
+	}
+
+	switch {
+	case links > f.maxLinks:
+		return &ModerationVerdict{Action: f.action, Reason: fmt.Sprintf("more than %d links", f.maxLinks)}, nil
+	case shortened:
+		return &ModerationVerdict{Action: f.action, Reason: "shortened links"}, nil
+	}
+	return nil, nil
+}
+
+// suspensionFilter rejects anything from suspended users.
+type suspensionFilter struct {
+	userRepo repositories.UserRepo
+}
+
+func NewSuspensionFilter(userRepo repositories.UserRepo) ContentFilter {
+	return &suspensionFilter{userRepo: userRepo}
+}
+
+func (f *suspensionFilter) Check(input *ModerationInput) (*ModerationVerdict, error) {
+	user, err := f.userRepo.GetUserById(int64(input.UserId))
+	if err != nil {
+		return nil, err
+	}
+	if user.Suspended(time.Now()) {
+		return &ModerationVerdict{Action: models.ModerationReject, Reason: "your account is suspended"}, nil
+	}
+	return nil, nil
+}
+
+// postingRateFilter rejects new content from users who already posted limit
+// items of the same kind within window.
+type postingRateFilter struct {
+	limit       int
+	window      time.Duration
+	commentRepo repositories.CommentRepo
+	eventRepo   repositories.EventRepo
+}
+
+func NewPostingRateFilter(limit int, window time.Duration, commentRepo repositories.CommentRepo, eventRepo repositories.EventRepo) ContentFilter {
+	return &postingRateFilter{
+		limit:       limit,
+		window:      window,
+		commentRepo: commentRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+func (f *postingRateFilter) Check(input *ModerationInput) (*ModerationVerdict, error) {
+	if !input.Create || f.limit <= 0 {
+		return nil, nil
+	}
+
+	since := time.Now().Add(-f.window)
+	var (
+		posted int64
+		err    error
+	)
+	switch input.TargetType {
+	case models.ModerationTargetComment:
+		posted, err = f.commentRepo.CountUserCommentsSince(input.UserId, since)
+	case models.ModerationTargetEvent:
+		posted, err = f.eventRepo.CountUserEventsSince(input.UserId, since)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if posted >= int64(f.limit) {
+		return &ModerationVerdict{Action: models.ModerationReject, Reason: "you are posting too fast, try again later"}, nil
+	}
+	return nil, nil
+}
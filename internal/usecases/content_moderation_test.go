@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+	"together-backend/internal/models"
+)
+
+func TestContentModerator(t *testing.T) {
+	now := time.Now()
+	users := &fakeUserRepo{users: map[int64]models.User{
+		1: {Id: 1},
+		2: {Id: 2, SuspendedAt: &now},
+	}}
+	tests := []struct {
+		name       string
+		userId     int
+		create     bool
+		posted     int64
+		text       string
+		wantAction string
+		wantText   string
+	}{
+		{"clean", 1, true, 0, "See you at the meetup", models.ModerationAllow, "See you at the meetup"},
+		{"blocked word", 1, true, 0, "You DO NGỐC!", models.ModerationMask, "You ** ****!"},
+		{"blocked phrase only", 1, true, 0, "do not go", models.ModerationAllow, "do not go"},
+		{"too many links", 1, true, 0, "http://a.io www.b.io https://c.io/x", models.ModerationQueue, "http://a.io www.b.io https://c.io/x"},
+		{"shortened link", 1, true, 0, "tickets at https://bit.ly/x", models.ModerationQueue, "tickets at https://bit.ly/x"},
+		{"posting too fast", 1, true, 2, "again", models.ModerationReject, "again"},
+		{"edits are not rate limited", 1, false, 2, "again", models.ModerationAllow, "again"},
+		{"suspended", 2, true, 0, "hello", models.ModerationReject, "hello"},
+		{"strictest wins", 1, true, 0, "do ngoc http://a.io http://b.io http://c.io", models.ModerationQueue, "** **** http://a.io http://b.io http://c.io"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderator := NewContentModerator(
+				NewSuspensionFilter(users),
+				NewWordListFilter([]string{"do ngoc"}, models.ModerationMask),
+				NewLinkSpamFilter(2, models.ModerationQueue),
+				NewPostingRateFilter(2, time.Minute, &fakeCommentRepo{posted: tt.posted}, &fakeEventRepo{posted: tt.posted}),
+			)
+			text := tt.text
+			verdict, err := moderator.Moderate(&ModerationInput{
+				UserId:     tt.userId,
+				TargetType: models.ModerationTargetComment,
+				Create:     tt.create,
+				Fields:     []*string{&text},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verdict.Action != tt.wantAction {
+				t.Errorf("Moderate(%q) = %s (%s), want %s", tt.text, verdict.Action, verdict.Reason, tt.wantAction)
+			}
+			if text != tt.wantText {
+				t.Errorf("Moderate(%q) left %q, want %q", tt.text, text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestCreateEventModeration(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantErr  bool
+		wantHold bool
+	}{
+		{"allowed", "Bring snacks", false, false},
+		{"queued", "Join via https://bit.ly/x", false, true},
+		{"rejected", "spam spam", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &fakeEventRepo{}
+			moderation := &fakeModerationRepo{}
+			uc := &eventUsecase{
+				eventAuthorizer: eventAuthorizer{eventRepo: events},
+				moderationRepo:  moderation,
+				moderator: NewContentModerator(
+					NewWordListFilter([]string{"spam"}, models.ModerationReject),
+					NewLinkSpamFilter(3, models.ModerationQueue),
+				),
+			}
+			start := time.Now().Add(time.Hour)
+			event, err := uc.CreateEventUsecase(&ReqBodyEvent{
+				Title:     "Meetup",
+				Content:   tt.content,
+				CreatedBy: 1,
+				StartTime: start,
+				EndTime:   start.Add(time.Hour),
+			}, nil)
+			if tt.wantErr {
+				if err == nil || len(events.events) > 0 {
+					t.Fatalf("CreateEventUsecase = %v, %v, want an error and no event", event, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if held := len(moderation.held) > 0; held != tt.wantHold || (event.HiddenAt != nil) != tt.wantHold {
+				t.Errorf("event held %v and hidden at %v, want held %v", held, event.HiddenAt, tt.wantHold)
+			}
+			if tt.wantHold {
+				item := moderation.held[0]
+				if item.TargetType != models.ModerationTargetEvent || item.TargetId != event.Id || item.Source != models.ModerationSourceFilter {
+					t.Errorf("held item %+v, want the event from the filter", item)
+				}
+			}
+		})
+	}
+}
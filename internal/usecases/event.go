@@ -5,22 +5,43 @@ import (
 	"time"
 	"together-backend/internal/models"
 	"together-backend/internal/repositories"
+	"unicode/utf8"
 )
 
 type EventUseCase interface {
 	CreateEventUsecase(reqBody *ReqBodyEvent, imageUrl []string) (*models.Event, error)
-	GetEventsUsecase(page, size, userId int, search, qType string) ([]EventsCreatedByUser, int64, error)
-	GetEventDetailUsecase(eventId int) (*EventsCreatedByUser, error)
+	GetEventsUsecase(query *repositories.EventQuery, pagination *repositories.Pagination) ([]EventsCreatedByUser, *repositories.PageInfo, int64, error)
+	GetEventDetailUsecase(eventId, viewerId int) (*EventsCreatedByUser, error)
+	GetEventGalleryUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.GalleryImage, *repositories.PageInfo, error)
 	DeleteEventUsecase(eventId, userId int) (string, error)
-	UpdateEventUsecase(userId int, reqBody *ReqBodyEditEvent, imageUrl []string) (*models.Event, error)
-	JoinEventUsecase(userId, eventId int) (*EventsCreatedByUser, string, error)
+	UpdateEventUsecase(userId int, reqBody *ReqBodyEditEvent, imageUrl []string) (*models.Event, models.FieldChanges, error)
+	JoinEventUsecase(userId, eventId int, strict bool) (*EventsCreatedByUser, string, *JoinWarning, error)
+	GetTicketUsecase(userId, eventId int) (*Ticket, error)
+	CheckInUsecase(organizerId, eventId int, payload string) (*models.UserEvent, error)
+	ChangeEventStatusUsecase(userId, eventId int, reqBody *ReqBodyEventStatus) (*EventsCreatedByUser, error)
+	PublishDueEventsUsecase(now time.Time) (int, error)
+	DuplicateEventUsecase(userId, eventId int, reqBody *ReqBodyDuplicateEvent) (*models.Event, error)
+	GetEventHistoryUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.EventRevision, *repositories.PageInfo, error)
+	CreateReviewUsecase(userId, eventId int, reqBody *ReqBodyReview) (*models.EventReview, error)
+	GetReviewsUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.EventReview, *RatingSummary, *repositories.PageInfo, error)
+	GetUserScheduleUsecase(userId, viewerId int, from, to *time.Time) ([]ScheduleBlock, error)
+	ExportAttendeesUsecase(userId, eventId int, format string) (*RosterExport, error)
+	ToggleEventReactionUsecase(reqBody *ReqBodyReaction, eventId, userId int) ([]models.ReactionCount, bool, error)
 }
 
 type eventUsecase struct {
-	eventRepo     repositories.EventRepo
-	userRepo      repositories.UserRepo
-	imageRepo     repositories.ImageRepo
-	userEventRepo repositories.UserEventRepo
+	eventAuthorizer
+	userRepo          repositories.UserRepo
+	imageRepo         repositories.ImageRepo
+	userEventRepo     repositories.UserEventRepo
+	notificationRepo  repositories.NotificationRepo
+	eventRevisionRepo repositories.EventRevisionRepo
+	eventReviewRepo   repositories.EventReviewRepo
+	eventSessionRepo  repositories.EventSessionRepo
+	reactionRepo      repositories.ReactionRepo
+	moderationRepo    repositories.ModerationRepo
+	moderator         *ContentModerator
+	uow               repositories.UnitOfWork
 }
 
 type ReqBodyEvent struct {
@@ -31,11 +52,18 @@ type ReqBodyEvent struct {
 	EndTime        time.Time
 	Location       int
 	DetailLocation string
+	Status         string
+	PublishAt      *time.Time
+	TemplateId     int
 }
 
 type EventsCreatedByUser struct {
-	EventDetail   models.Event `json:"event_detail"`
-	CreatedByUser models.User  `json:"created_by_user"`
+	EventDetail   models.Event           `json:"event_detail"`
+	CreatedByUser models.User            `json:"created_by_user"`
+	Attendance    *AttendanceStats       `json:"attendance,omitempty"`
+	Rating        *RatingSummary         `json:"rating,omitempty"`
+	Reputation    *OrganizerReputation   `json:"organizer_reputation,omitempty"`
+	Reactions     []models.ReactionCount `json:"reactions,omitempty"`
 }
 
 type ReqBodyEditEvent struct {
@@ -47,14 +75,45 @@ type ReqBodyEditEvent struct {
 	EndTime        time.Time
 	Location       int
 	DetailLocation string
+	Version        int
 }
 
-func NewEventUsecase(eventRepo repositories.EventRepo, userRepo repositories.UserRepo, imageRepo repositories.ImageRepo, userEventRepo repositories.UserEventRepo) EventUseCase {
+func NewEventUsecase(eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo, userRepo repositories.UserRepo, imageRepo repositories.ImageRepo, userEventRepo repositories.UserEventRepo, notificationRepo repositories.NotificationRepo, eventRevisionRepo repositories.EventRevisionRepo, eventReviewRepo repositories.EventReviewRepo, eventSessionRepo repositories.EventSessionRepo, reactionRepo repositories.ReactionRepo, moderationRepo repositories.ModerationRepo, moderator *ContentModerator, uow repositories.UnitOfWork) EventUseCase {
 	return &eventUsecase{
-		eventRepo:     eventRepo,
-		userRepo:      userRepo,
-		imageRepo:     imageRepo,
-		userEventRepo: userEventRepo,
+		eventAuthorizer:   eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		userRepo:          userRepo,
+		imageRepo:         imageRepo,
+		userEventRepo:     userEventRepo,
+		notificationRepo:  notificationRepo,
+		eventRevisionRepo: eventRevisionRepo,
+		eventReviewRepo:   eventReviewRepo,
+		eventSessionRepo:  eventSessionRepo,
+		reactionRepo:      reactionRepo,
+		moderationRepo:    moderationRepo,
+		moderator:         moderator,
+		uow:               uow,
+	}
+}
+
+func (uc *eventUsecase) inTransaction(fn func(tx *eventUsecase) error) error {
+	return inTransaction(uc.uow, uc, func(tx transactional) error {
+		return fn(tx.(*eventUsecase))
+	})
+}
+
+func (uc *eventUsecase) withRepositories(repos *repositories.Repositories) transactional {
+	return &eventUsecase{
+		eventAuthorizer:   eventAuthorizer{eventRepo: repos.Events, eventRoleRepo: repos.EventRoles},
+		userRepo:          repos.Users,
+		imageRepo:         repos.Images,
+		userEventRepo:     repos.UserEvents,
+		notificationRepo:  repos.Notifications,
+		eventRevisionRepo: repos.EventRevisions,
+		eventReviewRepo:   repos.EventReviews,
+		eventSessionRepo:  repos.EventSessions,
+		reactionRepo:      repos.Reactions,
+		moderationRepo:    repos.Moderation,
+		moderator:         uc.moderator,
 	}
 }
 
@@ -65,6 +124,9 @@ func (uc *eventUsecase) CreateEventUsecase(reqBody *ReqBodyEvent, imageUrl []str
 	if reqBody.Content == "" {
 		return nil, fmt.Errorf("content cannot be empty")
 	}
+	if utf8.RuneCountInString(reqBody.Content) > models.MaxEventContentLength {
+		return nil, fmt.Errorf("content must be at most %d characters", models.MaxEventContentLength)
+	}
 	if reqBody.CreatedBy == uint64(0) {
 		return nil, fmt.Errorf("created_by cannot be empty")
 	}
@@ -75,47 +137,98 @@ func (uc *eventUsecase) CreateEventUsecase(reqBody *ReqBodyEvent, imageUrl []str
 	if currentTime.After(reqBody.EndTime) {
 		return nil, fmt.Errorf("end time must be greater than current time")
 	}
-	newEvent, err := uc.eventRepo.CreateEvent(reqBody.Title, reqBody.Content, imageUrl, reqBody.CreatedBy, reqBody.StartTime, reqBody.EndTime, reqBody.Location, reqBody.DetailLocation)
+	if reqBody.Status == "" {
+		reqBody.Status = models.EventStatusPublished
+	}
+	if reqBody.Status != models.EventStatusPublished && reqBody.Status != models.EventStatusDraft {
+		return nil, fmt.Errorf("new event must be published or draft")
+	}
+	if reqBody.PublishAt != nil {
+		if currentTime.After(*reqBody.PublishAt) {
+			return nil, fmt.Errorf("publish time must be greater than current time")
+		}
+		if reqBody.PublishAt.After(reqBody.EndTime) {
+			return nil, fmt.Errorf("publish time must be less than end time")
+		}
+		reqBody.Status = models.EventStatusDraft
+	}
+	verdict, err := uc.moderateEvent(int(reqBody.CreatedBy), true, &reqBody.Title, &reqBody.Content)
+	if err != nil {
+		return nil, err
+	}
+	// a held event is hidden in the same transaction that creates it, so it
+	// is never public
+	var newEvent *models.Event
+	err = uc.inTransaction(func(tx *eventUsecase) error {
+		newEvent, err = tx.eventRepo.CreateEvent(reqBody.Title, reqBody.Content, imageUrl, reqBody.CreatedBy, reqBody.StartTime, reqBody.EndTime, reqBody.Location, reqBody.DetailLocation, reqBody.Status, reqBody.PublishAt)
+		if err != nil {
+			return err
+		}
+		if verdict.Action == models.ModerationQueue {
+			return tx.holdEvent(newEvent, verdict.Reason)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	return newEvent, nil
 }
 
-func (uc *eventUsecase) GetEventsUsecase(page, size, userId int, search, qType string) ([]EventsCreatedByUser, int64, error) {
+func (uc *eventUsecase) GetEventsUsecase(query *repositories.EventQuery, pagination *repositories.Pagination) ([]EventsCreatedByUser, *repositories.PageInfo, int64, error) {
 	var eventsCreatedByUsers []EventsCreatedByUser
 
-	total, err := uc.eventRepo.CountEvents(userId, search, qType)
+	if err := query.Validate(); err != nil {
+		return nil, nil, int64(0), err
+	}
+
+	pagination.Normalize()
+
+	total, err := uc.eventRepo.CountEvents(query)
+	if err != nil {
+		return nil, nil, int64(0), err
+	}
+
+	events, pageInfo, err := uc.eventRepo.GetEvents(query, pagination)
 	if err != nil {
-		return nil, int64(0), err
+		return nil, nil, int64(0), err
 	}
 
-	events, err := uc.eventRepo.GetEvents(page, size, userId, search, qType)
+	var organizerIds []uint64
+	seen := make(map[uint64]bool)
+	for _, event := range events {
+		if !seen[event.CreatedBy] {
+			seen[event.CreatedBy] = true
+			organizerIds = append(organizerIds, event.CreatedBy)
+		}
+	}
+	reputations, err := uc.organizerReputations(organizerIds)
 	if err != nil {
-		return nil, int64(0), err
+		return nil, nil, int64(0), err
 	}
 
 	for i := 0; i < len(events); i++ {
 		createdByUser, err := uc.userRepo.GetUserById(int64(events[i].CreatedBy))
 		if err != nil {
-			return nil, int64(0), fmt.Errorf("failed to get user who created the event")
+			return nil, nil, int64(0), fmt.Errorf("failed to get user who created the event")
 		}
 		eventsCreatedByUser := EventsCreatedByUser{
 			EventDetail:   events[i],
 			CreatedByUser: createdByUser,
+			Reputation:    reputations[events[i].CreatedBy],
 		}
 		eventsCreatedByUsers = append(eventsCreatedByUsers, eventsCreatedByUser)
 	}
 
-	return eventsCreatedByUsers, total, nil
+	return eventsCreatedByUsers, pageInfo, total, nil
 }
 
-func (uc *eventUsecase) GetEventDetailUsecase(eventId int) (*EventsCreatedByUser, error) {
+func (uc *eventUsecase) GetEventDetailUsecase(eventId, viewerId int) (*EventsCreatedByUser, error) {
 	if eventId <= 0 {
 		return nil, fmt.Errorf("invalid event id")
 	}
 
-	event, err := uc.eventRepo.GetEventDetail(eventId)
+	event, err := uc.visibleEvent(eventId, viewerId)
 	if err != nil {
 		return nil, err
 	}
@@ -125,9 +238,33 @@ func (uc *eventUsecase) GetEventDetailUsecase(eventId int) (*EventsCreatedByUser
 		return nil, err
 	}
 
+	attendance, err := uc.attendanceStats(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := uc.ratingSummary(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	reputations, err := uc.organizerReputations([]uint64{event.CreatedBy})
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := uc.reactionRepo.GetReactionCounts(models.ReactionTargetEvent, []uint{event.Id}, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
 	eventsCreatedByUser := EventsCreatedByUser{
 		EventDetail:   event,
 		CreatedByUser: createdByUser,
+		Attendance:    attendance,
+		Rating:        rating,
+		Reputation:    reputations[event.CreatedBy],
+		Reactions:     reactionCounts(reactions[event.Id]),
 	}
 	return &eventsCreatedByUser, nil
 }
@@ -140,14 +277,18 @@ func (uc *eventUsecase) DeleteEventUsecase(eventId, userId int) (string, error)
 		return "", fmt.Errorf("invalid user id")
 	}
 
-	event, err := uc.eventRepo.GetEventByEventIdAndCreatedBy(eventId, userId)
-	if err != nil {
-		return "", err
-	}
+	var mess string
+	err := uc.inTransaction(func(tx *eventUsecase) error {
+		event, _, err := tx.authorize(eventId, userId, models.EventRoleOwner)
+		if err != nil {
+			return err
+		}
 
-	fmt.Println("event will delete: ", event)
+		fmt.Println("event will delete: ", event)
 
-	mess, err := uc.eventRepo.DeleteEvent(event)
+		mess, err = tx.eventRepo.DeleteEvent(event)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
@@ -155,73 +296,213 @@ func (uc *eventUsecase) DeleteEventUsecase(eventId, userId int) (string, error)
 	return mess, nil
 }
 
-func (uc *eventUsecase) UpdateEventUsecase(userId int, reqBody *ReqBodyEditEvent, imageUrl []string) (*models.Event, error) {
+func (uc *eventUsecase) UpdateEventUsecase(userId int, reqBody *ReqBodyEditEvent, imageUrl []string) (*models.Event, models.FieldChanges, error) {
 	if reqBody.Title == "" {
-		return nil, fmt.Errorf("title cannot be empty")
+		return nil, nil, fmt.Errorf("title cannot be empty")
 	}
 	if reqBody.Content == "" {
-		return nil, fmt.Errorf("content cannot be empty")
+		return nil, nil, fmt.Errorf("content cannot be empty")
+	}
+	if utf8.RuneCountInString(reqBody.Content) > models.MaxEventContentLength {
+		return nil, nil, fmt.Errorf("content must be at most %d characters", models.MaxEventContentLength)
 	}
 	if reqBody.CreatedBy == uint64(0) {
-		return nil, fmt.Errorf("created_by cannot be empty")
+		return nil, nil, fmt.Errorf("created_by cannot be empty")
 	}
 	if reqBody.StartTime.After(reqBody.EndTime) {
-		return nil, fmt.Errorf("start time must be less than end time")
+		return nil, nil, fmt.Errorf("start time must be less than end time")
 	}
-	event, err := uc.eventRepo.GetEventByEventIdAndCreatedBy(int(reqBody.Id), userId)
+	var (
+		updatedEvent models.Event
+		changes      models.FieldChanges
+	)
+	err := uc.inTransaction(func(tx *eventUsecase) error {
+		event, _, err := tx.authorize(int(reqBody.Id), userId, models.EventRoleCoOrganizer)
+		if err != nil {
+			return err
+		}
+		if event.Version != reqBody.Version {
+			return repositories.ErrVersionConflict
+		}
+
+		outside, err := tx.eventSessionRepo.CountSessionsOutside(int(event.Id), reqBody.StartTime, reqBody.EndTime)
+		if err != nil {
+			return err
+		}
+		if outside > 0 {
+			return fmt.Errorf("%d sessions would fall outside the new event time", outside)
+		}
+
+		verdict := &ModerationVerdict{Action: models.ModerationAllow}
+		if reqBody.Title != event.Title || reqBody.Content != event.Content {
+			verdict, err = tx.moderateEvent(userId, false, &reqBody.Title, &reqBody.Content)
+			if err != nil {
+				return err
+			}
+		}
+
+		changes = diffEvent(&event, reqBody, imageUrl)
+
+		_, err = tx.eventRepo.UpdateEvent(event, reqBody.Title, reqBody.Content, imageUrl, reqBody.StartTime, reqBody.EndTime, reqBody.Location, reqBody.DetailLocation)
+		if err != nil {
+			return err
+		}
+
+		if len(changes) > 0 {
+			if _, err := tx.eventRevisionRepo.CreateRevision(int(event.Id), userId, changes); err != nil {
+				return err
+			}
+			if summary := changeSummary(reqBody.Title, changes); summary != "" {
+				if err := tx.notifyAttendees(int(event.Id), userId, models.NotificationEventUpdated, summary); err != nil {
+					return err
+				}
+			}
+		}
+
+		if verdict.Action == models.ModerationQueue && event.HiddenAt == nil {
+			if err := tx.holdEvent(&event, verdict.Reason); err != nil {
+				return err
+			}
+		}
+
+		updatedEvent, err = tx.eventRepo.GetEventDetail(int(event.Id))
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return &updatedEvent, changes, nil
+}
 
-	updatedEvent, err := uc.eventRepo.UpdateEvent(event, reqBody.Title, reqBody.Content, imageUrl, reqBody.StartTime, reqBody.EndTime, reqBody.Location, reqBody.DetailLocation)
+// JoinEventUsecase toggles the user's membership. Joining an event that
+// overlaps the user's other events returns a warning, or fails with
+// ScheduleConflictError when strict is set.
+func (uc *eventUsecase) JoinEventUsecase(userId, eventId int, strict bool) (*EventsCreatedByUser, string, *JoinWarning, error) {
+	var (
+		eventsCreatedByUser *EventsCreatedByUser
+		mess                string
+		warning             *JoinWarning
+	)
+	// the membership check and the toggle run in one serializable
+	// transaction, so concurrent joins cannot both insert
+	err := uc.inTransaction(func(tx *eventUsecase) error {
+		var err error
+		eventsCreatedByUser, mess, warning, err = tx.joinEvent(userId, eventId, strict)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, "", nil, err
 	}
-	return updatedEvent, nil
+	return eventsCreatedByUser, mess, warning, nil
 }
 
-func (uc *eventUsecase) JoinEventUsecase(userId, eventId int) (*EventsCreatedByUser, string, error) {
+func (uc *eventUsecase) joinEvent(userId, eventId int, strict bool) (*EventsCreatedByUser, string, *JoinWarning, error) {
 
 	var (
-		event models.Event
-		mess  string
+		event   models.Event
+		mess    string
+		warning *JoinWarning
 	)
 
 	userEventGet, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
 	if err != nil && err.Error() != "record not found" {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
 	if userEventGet != nil {
 		userEventRemove, err := uc.userEventRepo.RemoveUserFromEvent(userId, eventId)
 		if err != nil {
-			return nil, "", err
+			return nil, "", nil, err
+		}
+		if err := uc.eventSessionRepo.RemoveUserSignups(userId, eventId); err != nil {
+			return nil, "", nil, err
 		}
 		event, err = uc.eventRepo.GetEventDetail(int(userEventRemove.EventId))
 		if err != nil {
-			return nil, "", err
+			return nil, "", nil, err
 		}
 		mess = "removed from the event successfully"
 	} else {
+		event, err = uc.visibleEvent(eventId, userId)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if event.Status != models.EventStatusPublished && event.Status != models.EventStatusPostponed {
+			return nil, "", nil, fmt.Errorf("cannot join a %s event", event.Status)
+		}
+		if time.Now().After(event.EndTime) {
+			return nil, "", nil, fmt.Errorf("cannot join an event that has ended")
+		}
+		conflicts, err := uc.scheduleConflicts(userId, &event)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if len(conflicts) > 0 {
+			if strict {
+				return nil, "", nil, &ScheduleConflictError{Conflicts: conflicts}
+			}
+			warning = &JoinWarning{
+				Type:      "schedule_conflict",
+				Message:   fmt.Sprintf("this event overlaps %d of your other events", len(conflicts)),
+				Conflicts: conflicts,
+			}
+		}
 		userEventAdd, err := uc.userEventRepo.AddUserToEvent(userId, eventId)
 		if err != nil {
-			return nil, "", err
+			return nil, "", nil, err
 		}
 		event, err = uc.eventRepo.GetEventDetail(int(userEventAdd.EventId))
 		if err != nil {
-			return nil, "", err
+			return nil, "", nil, err
 		}
 		mess = "joined the event successfully"
 	}
 
 	createdByUser, err := uc.userRepo.GetUserById(int64(event.CreatedBy))
 	if err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
 	eventsCreatedByUser := EventsCreatedByUser{
 		EventDetail:   event,
 		CreatedByUser: createdByUser,
 	}
-	return &eventsCreatedByUser, mess, nil
+	return &eventsCreatedByUser, mess, warning, nil
+}
+
+// moderateEvent runs the title and content of an event through the content
+// filters, masking them in place when they ask to.
+func (uc *eventUsecase) moderateEvent(userId int, create bool, title, content *string) (*ModerationVerdict, error) {
+	verdict, err := uc.moderator.Moderate(&ModerationInput{
+		UserId:     userId,
+		TargetType: models.ModerationTargetEvent,
+		Create:     create,
+		Fields:     []*string{title, content},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Action == models.ModerationReject {
+		return nil, fmt.Errorf("event was rejected: %s", verdict.Reason)
+	}
+	return verdict, nil
+}
+
+// holdEvent hides the event from everyone but its staff until a moderator
+// reviews it.
+func (uc *eventUsecase) holdEvent(event *models.Event, reason string) error {
+	now := time.Now()
+	_, err := uc.moderationRepo.HoldContent(&models.ModerationItem{
+		TargetType: models.ModerationTargetEvent,
+		TargetId:   event.Id,
+		EventId:    &event.Id,
+		AuthorId:   uint(event.CreatedBy),
+		Source:     models.ModerationSourceFilter,
+		Reason:     reason,
+	}, now)
+	if err != nil {
+		return err
+	}
+	event.HiddenAt = &now
+	return nil
 }
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/pkg"
+)
+
+const ticketVersion = "TGT1"
+
+type Ticket struct {
+	EventId     int        `json:"event_id"`
+	UserId      int        `json:"user_id"`
+	Payload     string     `json:"payload"`
+	CheckedInAt *time.Time `json:"checked_in_at"`
+}
+
+type AttendanceStats struct {
+	Attendees int64   `json:"attendees"`
+	CheckedIn int64   `json:"checked_in"`
+	Rate      float64 `json:"rate"`
+}
+
+func ticketPayload(eventId, userId int) string {
+	event, user := strconv.Itoa(eventId), strconv.Itoa(userId)
+	return strings.Join([]string{ticketVersion, event, user, pkg.Sign(ticketVersion, event, user)}, ".")
+}
+
+// parseTicketPayload returns the event and user ids of a payload signed by ticketPayload.
+func parseTicketPayload(payload string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(payload), ".")
+	if len(parts) != 4 || parts[0] != ticketVersion {
+		return 0, 0, fmt.Errorf("invalid ticket")
+	}
+	if !pkg.VerifySignature(parts[3], parts[0], parts[1], parts[2]) {
+		return 0, 0, fmt.Errorf("invalid ticket signature")
+	}
+	eventId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ticket")
+	}
+	userId, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid ticket")
+	}
+	return eventId, userId, nil
+}
+
+func (uc *eventUsecase) GetTicketUsecase(userId, eventId int) (*Ticket, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	userEvent, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, err
+	}
+	if userEvent == nil {
+		return nil, fmt.Errorf("you haven't joined in the event yet")
+	}
+
+	return &Ticket{
+		EventId:     eventId,
+		UserId:      userId,
+		Payload:     ticketPayload(eventId, userId),
+		CheckedInAt: userEvent.CheckedInAt,
+	}, nil
+}
+
+func (uc *eventUsecase) CheckInUsecase(organizerId, eventId int, payload string) (*models.UserEvent, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if organizerId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	_, _, err := uc.authorize(eventId, organizerId, models.EventRoleModerator)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketEventId, userId, err := parseTicketPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	if ticketEventId != eventId {
+		return nil, fmt.Errorf("ticket belongs to another event")
+	}
+
+	userEvent, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
+	if err != nil && err.Error() != "record not found" {
+		return nil, err
+	}
+	if userEvent == nil {
+		return nil, fmt.Errorf("user hasn't joined in the event")
+	}
+	if userEvent.CheckedInAt != nil {
+		return nil, fmt.Errorf("user has already checked in")
+	}
+
+	return uc.userEventRepo.CheckIn(userId, eventId, time.Now())
+}
+
+func (uc *eventUsecase) attendanceStats(eventId int) (*AttendanceStats, error) {
+	attendees, checkedIn, err := uc.userEventRepo.CountAttendance(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := AttendanceStats{Attendees: attendees, CheckedIn: checkedIn}
+	if attendees > 0 {
+		stats.Rate = float64(checkedIn) / float64(attendees)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,61 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+	"together-backend/internal/models"
+)
+
+type ReqBodyDuplicateEvent struct {
+	StartTime time.Time `json:"start_time"`
+	Publish   bool      `json:"publish"`
+}
+
+// DuplicateEventUsecase copies an event to a new start time, keeping its
+// duration and images. The copy is a draft unless Publish is set.
+func (uc *eventUsecase) DuplicateEventUsecase(userId, eventId int, reqBody *ReqBodyDuplicateEvent) (*models.Event, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if reqBody.StartTime.IsZero() {
+		return nil, fmt.Errorf("start time cannot be empty")
+	}
+
+	event, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	// a held or hidden event must not come back as a public copy
+	if event.HiddenAt != nil {
+		user, err := uc.userRepo.GetUserById(int64(userId))
+		if err != nil {
+			return nil, err
+		}
+		if user.Role != models.UserRoleModerator {
+			return nil, fmt.Errorf("a hidden event cannot be duplicated")
+		}
+	}
+
+	var imageUrl []string
+	for _, image := range event.EventImages {
+		imageUrl = append(imageUrl, image.ImageUrl)
+	}
+
+	status := models.EventStatusDraft
+	if reqBody.Publish {
+		status = models.EventStatusPublished
+	}
+
+	// the copy is new content, so it is validated and moderated like any
+	// other new event
+	return uc.CreateEventUsecase(&ReqBodyEvent{
+		Title:          event.Title,
+		Content:        event.Content,
+		CreatedBy:      uint64(userId),
+		StartTime:      reqBody.StartTime,
+		EndTime:        reqBody.StartTime.Add(event.EndTime.Sub(event.StartTime)),
+		Location:       event.Location,
+		DetailLocation: event.DetailLocation,
+		Status:         status,
+	}, imageUrl)
+}
@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"fmt"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// GetEventGalleryUsecase lists the images of an event and of its comments,
+// newest first. Images of hidden comments are left out.
+func (uc *eventUsecase) GetEventGalleryUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.GalleryImage, *repositories.PageInfo, error) {
+	if eventId <= 0 {
+		return nil, nil, fmt.Errorf("invalid event id")
+	}
+	pagination.Normalize()
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, nil, err
+	}
+
+	return uc.imageRepo.GetEventGallery(eventId, pagination)
+}
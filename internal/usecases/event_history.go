@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// diffEvent lists the fields an update changes on event.
+func diffEvent(event *models.Event, reqBody *ReqBodyEditEvent, imageUrl []string) models.FieldChanges {
+	var changes models.FieldChanges
+	if event.Title != reqBody.Title {
+		changes = append(changes, models.FieldChange{Field: "title", Old: event.Title, New: reqBody.Title})
+	}
+	if event.Content != reqBody.Content {
+		changes = append(changes, models.FieldChange{Field: "content", Old: event.Content, New: reqBody.Content})
+	}
+	if !event.StartTime.Equal(reqBody.StartTime) {
+		changes = append(changes, models.FieldChange{Field: "start_time", Old: event.StartTime, New: reqBody.StartTime})
+	}
+	if !event.EndTime.Equal(reqBody.EndTime) {
+		changes = append(changes, models.FieldChange{Field: "end_time", Old: event.EndTime, New: reqBody.EndTime})
+	}
+	if event.Location != reqBody.Location {
+		changes = append(changes, models.FieldChange{Field: "location", Old: event.Location, New: reqBody.Location})
+	}
+	if event.DetailLocation != reqBody.DetailLocation {
+		changes = append(changes, models.FieldChange{Field: "detail_location", Old: event.DetailLocation, New: reqBody.DetailLocation})
+	}
+
+	oldImages := []string{}
+	for _, image := range event.EventImages {
+		oldImages = append(oldImages, image.ImageUrl)
+	}
+	newImages := append([]string{}, imageUrl...)
+	if strings.Join(oldImages, "\n") != strings.Join(newImages, "\n") {
+		changes = append(changes, models.FieldChange{Field: "event_images", Old: oldImages, New: newImages})
+	}
+	return changes
+}
+
+// changeSummary describes the changes attendees care about, time and place.
+func changeSummary(title string, changes models.FieldChanges) string {
+	var parts []string
+	for _, change := range changes {
+		switch change.Field {
+		case "start_time":
+			parts = append(parts, "starts at "+change.New.(time.Time).Format("2006-01-02 15:04"))
+		case "end_time":
+			parts = append(parts, "ends at "+change.New.(time.Time).Format("2006-01-02 15:04"))
+		case "location", "detail_location":
+			parts = append(parts, "has a new location")
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Event \"%s\" %s", title, strings.Join(parts, ", "))
+}
+
+func (uc *eventUsecase) GetEventHistoryUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.EventRevision, *repositories.PageInfo, error) {
+	if eventId <= 0 {
+		return nil, nil, fmt.Errorf("invalid event id")
+	}
+	pagination.Normalize()
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, nil, err
+	}
+
+	return uc.eventRevisionRepo.GetRevisions(eventId, pagination)
+}
@@ -0,0 +1,463 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 20
+)
+
+type EventPollUseCase interface {
+	GetPollsUsecase(eventId, viewerId int) ([]PollResult, error)
+	CreatePollUsecase(userId, eventId int, reqBody *ReqBodyPoll) (*PollResult, error)
+	VoteUsecase(userId, eventId, pollId int, reqBody *ReqBodyVote) (*PollResult, error)
+	ClosePollUsecase(userId, eventId, pollId int) (*PollResult, error)
+	DeletePollUsecase(userId, eventId, pollId int) (*models.EventPoll, error)
+	ApplyPollUsecase(userId, eventId, pollId int, reqBody *ReqBodyApplyPoll) (*models.Event, error)
+}
+
+type eventPollUsecase struct {
+	eventAuthorizer
+	eventPollRepo repositories.EventPollRepo
+	userEventRepo repositories.UserEventRepo
+	eventUsecase  EventUseCase
+	uow           repositories.UnitOfWork
+}
+
+type ReqBodyPoll struct {
+	Question       string              `json:"question"`
+	Type           string              `json:"type"`
+	MultipleChoice bool                `json:"multiple_choice"`
+	Anonymous      bool                `json:"anonymous"`
+	ClosesAt       *time.Time          `json:"closes_at"`
+	Options        []ReqBodyPollOption `json:"options"`
+}
+
+type ReqBodyPollOption struct {
+	Label     string     `json:"label"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+type ReqBodyVote struct {
+	OptionIds []uint `json:"option_ids"`
+}
+
+type ReqBodyApplyPoll struct {
+	OptionId uint `json:"option_id"`
+	Version  int  `json:"-"`
+}
+
+type PollResult struct {
+	Poll    models.EventPoll   `json:"poll"`
+	Closed  bool               `json:"closed"`
+	Voters  int                `json:"voters"`
+	Results []PollOptionResult `json:"results"`
+	MyVotes []uint             `json:"my_votes"`
+}
+
+// PollOptionResult is the tally of one option. Voters is left empty for
+// anonymous polls.
+type PollOptionResult struct {
+	Option models.PollOption `json:"option"`
+	Votes  int               `json:"votes"`
+	Voters []models.User     `json:"voters,omitempty"`
+}
+
+func NewEventPollUsecase(eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo, eventPollRepo repositories.EventPollRepo, userEventRepo repositories.UserEventRepo, eventUsecase EventUseCase, uow repositories.UnitOfWork) EventPollUseCase {
+	return &eventPollUsecase{
+		eventAuthorizer: eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		eventPollRepo:   eventPollRepo,
+		userEventRepo:   userEventRepo,
+		eventUsecase:    eventUsecase,
+		uow:             uow,
+	}
+}
+
+func (uc *eventPollUsecase) GetPollsUsecase(eventId, viewerId int) ([]PollResult, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, err
+	}
+
+	polls, err := uc.eventPollRepo.GetPolls(eventId)
+	if err != nil {
+		return nil, err
+	}
+	return uc.pollResults(polls, viewerId)
+}
+
+func (uc *eventPollUsecase) CreatePollUsecase(userId, eventId int, reqBody *ReqBodyPoll) (*PollResult, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if err := validatePoll(reqBody, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := models.EventPoll{
+		EventId:        event.Id,
+		CreatedBy:      uint(userId),
+		Question:       reqBody.Question,
+		Type:           reqBody.Type,
+		MultipleChoice: reqBody.MultipleChoice,
+		Anonymous:      reqBody.Anonymous,
+		ClosesAt:       reqBody.ClosesAt,
+	}
+	for i, option := range reqBody.Options {
+		poll.Options = append(poll.Options, models.PollOption{
+			Position:  i,
+			Label:     option.Label,
+			StartTime: option.StartTime,
+			EndTime:   option.EndTime,
+		})
+	}
+
+	newPoll, err := uc.eventPollRepo.CreatePoll(poll)
+	if err != nil {
+		return nil, err
+	}
+	return uc.pollResult(newPoll, userId)
+}
+
+func (uc *eventPollUsecase) VoteUsecase(userId, eventId, pollId int, reqBody *ReqBodyVote) (*PollResult, error) {
+	if eventId <= 0 || pollId <= 0 {
+		return nil, fmt.Errorf("invalid poll id")
+	}
+
+	var poll *models.EventPoll
+	err := uc.inTransaction(func(tx *eventPollUsecase) error {
+		if _, err := tx.visibleEvent(eventId, userId); err != nil {
+			return err
+		}
+		var err error
+		poll, err = tx.eventPollRepo.GetPoll(pollId, eventId)
+		if err != nil {
+			return err
+		}
+		if poll.Closed(time.Now()) {
+			return fmt.Errorf("poll is closed")
+		}
+
+		_, err = tx.userEventRepo.GetUserFromEvent(userId, eventId)
+		if err != nil {
+			if err.Error() == "record not found" {
+				return fmt.Errorf("only attendees can vote in this poll")
+			}
+			return err
+		}
+
+		optionIds, err := pollBallot(poll, reqBody.OptionIds)
+		if err != nil {
+			return err
+		}
+		_, err = tx.eventPollRepo.ReplaceVotes(pollId, userId, optionIds)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uc.pollResult(poll, userId)
+}
+
+func (uc *eventPollUsecase) ClosePollUsecase(userId, eventId, pollId int) (*PollResult, error) {
+	if eventId <= 0 || pollId <= 0 {
+		return nil, fmt.Errorf("invalid poll id")
+	}
+
+	_, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := uc.eventPollRepo.GetPoll(pollId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if poll.Closed(now) {
+		return nil, fmt.Errorf("poll is already closed")
+	}
+
+	poll, err = uc.eventPollRepo.ClosePoll(poll, now)
+	if err != nil {
+		return nil, err
+	}
+	return uc.pollResult(poll, userId)
+}
+
+func (uc *eventPollUsecase) DeletePollUsecase(userId, eventId, pollId int) (*models.EventPoll, error) {
+	if eventId <= 0 || pollId <= 0 {
+		return nil, fmt.Errorf("invalid poll id")
+	}
+
+	_, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := uc.eventPollRepo.GetPoll(pollId, eventId)
+	if err != nil {
+		return nil, err
+	}
+	return uc.eventPollRepo.DeletePoll(poll)
+}
+
+// ApplyPollUsecase moves the event to the winning option of a closed date
+// poll. A tie has to be broken by naming one of the tied options. The event
+// update and marking the poll applied share one transaction, so a poll is
+// applied once.
+func (uc *eventPollUsecase) ApplyPollUsecase(userId, eventId, pollId int, reqBody *ReqBodyApplyPoll) (*models.Event, error) {
+	if eventId <= 0 || pollId <= 0 {
+		return nil, fmt.Errorf("invalid poll id")
+	}
+
+	var updatedEvent *models.Event
+	err := uc.inTransaction(func(tx *eventPollUsecase) error {
+		event, _, err := tx.authorize(eventId, userId, models.EventRoleCoOrganizer)
+		if err != nil {
+			return err
+		}
+		if event.Version != reqBody.Version {
+			return repositories.ErrVersionConflict
+		}
+
+		poll, err := tx.eventPollRepo.GetPoll(pollId, eventId)
+		if err != nil {
+			return err
+		}
+		if poll.Type != models.PollTypeDate {
+			return fmt.Errorf("only date polls can be applied to the event")
+		}
+		if !poll.Closed(time.Now()) {
+			return fmt.Errorf("close the poll before applying it")
+		}
+		if poll.AppliedAt != nil {
+			return fmt.Errorf("poll has already been applied")
+		}
+
+		result, err := tx.pollResult(poll, userId)
+		if err != nil {
+			return err
+		}
+		winner, err := pollWinner(result, reqBody.OptionId)
+		if err != nil {
+			return err
+		}
+
+		var imageUrl []string
+		for _, image := range event.EventImages {
+			imageUrl = append(imageUrl, image.ImageUrl)
+		}
+		updatedEvent, _, err = tx.eventUsecase.UpdateEventUsecase(userId, &ReqBodyEditEvent{
+			Id:             uint64(event.Id),
+			Title:          event.Title,
+			Content:        event.Content,
+			CreatedBy:      event.CreatedBy,
+			StartTime:      *winner.StartTime,
+			EndTime:        *winner.EndTime,
+			Location:       event.Location,
+			DetailLocation: event.DetailLocation,
+			Version:        event.Version,
+		}, imageUrl)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.eventPollRepo.MarkApplied(poll, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updatedEvent, nil
+}
+
+func (uc *eventPollUsecase) pollResult(poll *models.EventPoll, viewerId int) (*PollResult, error) {
+	results, err := uc.pollResults([]models.EventPoll{*poll}, viewerId)
+	if err != nil {
+		return nil, err
+	}
+	return &results[0], nil
+}
+
+// pollResults tallies the polls from one query over their votes.
+func (uc *eventPollUsecase) pollResults(polls []models.EventPoll, viewerId int) ([]PollResult, error) {
+	pollIds := make([]uint, len(polls))
+	for i := range polls {
+		pollIds[i] = polls[i].Id
+	}
+	votes, err := uc.eventPollRepo.GetVotes(pollIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byOption := make(map[uint][]models.PollVote)
+	for _, vote := range votes {
+		byOption[vote.OptionId] = append(byOption[vote.OptionId], vote)
+	}
+
+	now := time.Now()
+	results := make([]PollResult, len(polls))
+	for i := range polls {
+		poll := polls[i]
+		result := PollResult{
+			Poll:    poll,
+			Closed:  poll.Closed(now),
+			Results: make([]PollOptionResult, len(poll.Options)),
+			MyVotes: []uint{},
+		}
+		voters := make(map[uint]bool)
+		for j, option := range poll.Options {
+			optionResult := PollOptionResult{Option: option, Votes: len(byOption[option.Id])}
+			for _, vote := range byOption[option.Id] {
+				voters[vote.UserId] = true
+				if vote.UserId == uint(viewerId) {
+					result.MyVotes = append(result.MyVotes, option.Id)
+				}
+				if !poll.Anonymous {
+					optionResult.Voters = append(optionResult.Voters, vote.User)
+				}
+			}
+			result.Results[j] = optionResult
+		}
+		result.Voters = len(voters)
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (uc *eventPollUsecase) inTransaction(fn func(tx *eventPollUsecase) error) error {
+	return inTransaction(uc.uow, uc, func(tx transactional) error {
+		return fn(tx.(*eventPollUsecase))
+	})
+}
+
+func (uc *eventPollUsecase) withRepositories(repos *repositories.Repositories) transactional {
+	tx := &eventPollUsecase{
+		eventAuthorizer: eventAuthorizer{eventRepo: repos.Events, eventRoleRepo: repos.EventRoles},
+		eventPollRepo:   repos.EventPolls,
+		userEventRepo:   repos.UserEvents,
+		eventUsecase:    uc.eventUsecase,
+	}
+	// event updates join the same transaction
+	if events, ok := uc.eventUsecase.(transactional); ok {
+		tx.eventUsecase = events.withRepositories(repos).(EventUseCase)
+	}
+	return tx
+}
+
+func validatePoll(reqBody *ReqBodyPoll, now time.Time) error {
+	reqBody.Question = strings.TrimSpace(reqBody.Question)
+	if reqBody.Question == "" {
+		return fmt.Errorf("question cannot be empty")
+	}
+	if reqBody.Type == "" {
+		reqBody.Type = models.PollTypeChoice
+	}
+	if reqBody.Type != models.PollTypeChoice && reqBody.Type != models.PollTypeDate {
+		return fmt.Errorf("poll type must be choice or date")
+	}
+	if reqBody.ClosesAt != nil && !reqBody.ClosesAt.After(now) {
+		return fmt.Errorf("closing time must be greater than current time")
+	}
+	if len(reqBody.Options) < minPollOptions || len(reqBody.Options) > maxPollOptions {
+		return fmt.Errorf("poll must have between %d and %d options", minPollOptions, maxPollOptions)
+	}
+
+	for i := range reqBody.Options {
+		option := &reqBody.Options[i]
+		option.Label = strings.TrimSpace(option.Label)
+		if reqBody.Type == models.PollTypeChoice {
+			option.StartTime, option.EndTime = nil, nil
+			if option.Label == "" {
+				return fmt.Errorf("option %d cannot be empty", i+1)
+			}
+			continue
+		}
+
+		if option.StartTime == nil || option.EndTime == nil {
+			return fmt.Errorf("option %d must have a start and end time", i+1)
+		}
+		if !option.StartTime.Before(*option.EndTime) {
+			return fmt.Errorf("option %d: start time must be less than end time", i+1)
+		}
+		if !option.StartTime.After(now) {
+			return fmt.Errorf("option %d: start time must be greater than current time", i+1)
+		}
+		if option.Label == "" {
+			option.Label = option.StartTime.Format("2006-01-02 15:04") + " - " + option.EndTime.Format("2006-01-02 15:04")
+		}
+	}
+	return nil
+}
+
+// pollBallot validates a ballot against the poll's options. An empty
+// ballot withdraws the user's vote.
+func pollBallot(poll *models.EventPoll, optionIds []uint) ([]uint, error) {
+	valid := make(map[uint]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.Id] = true
+	}
+
+	var ballot []uint
+	seen := make(map[uint]bool)
+	for _, optionId := range optionIds {
+		if !valid[optionId] {
+			return nil, fmt.Errorf("option %d does not belong to this poll", optionId)
+		}
+		if !seen[optionId] {
+			seen[optionId] = true
+			ballot = append(ballot, optionId)
+		}
+	}
+	if !poll.MultipleChoice && len(ballot) > 1 {
+		return nil, fmt.Errorf("this poll allows a single choice")
+	}
+	return ballot, nil
+}
+
+func pollWinner(result *PollResult, optionId uint) (*models.PollOption, error) {
+	most := 0
+	for _, optionResult := range result.Results {
+		if optionResult.Votes > most {
+			most = optionResult.Votes
+		}
+	}
+	if most == 0 {
+		return nil, fmt.Errorf("poll has no votes")
+	}
+
+	var winners []*models.PollOption
+	for i := range result.Results {
+		if result.Results[i].Votes == most {
+			winners = append(winners, &result.Results[i].Option)
+		}
+	}
+	if optionId == 0 {
+		if len(winners) > 1 {
+			return nil, fmt.Errorf("poll is tied, choose one of the winning options")
+		}
+		return winners[0], nil
+	}
+	for _, winner := range winners {
+		if winner.Id == optionId {
+			return winner, nil
+		}
+	}
+	return nil, fmt.Errorf("option %d is not a winning option", optionId)
+}
@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	"together-backend/internal/models"
+)
+
+func TestValidatePoll(t *testing.T) {
+	now := time.Now()
+	start, end := now.Add(time.Hour), now.Add(2*time.Hour)
+	past := now.Add(-time.Hour)
+	choices := []ReqBodyPollOption{{Label: "a"}, {Label: "b"}}
+	tests := []struct {
+		name    string
+		reqBody ReqBodyPoll
+		wantErr bool
+	}{
+		{"choice", ReqBodyPoll{Question: "Snacks?", Options: choices}, false},
+		{"date", ReqBodyPoll{Question: "When?", Type: models.PollTypeDate, Options: []ReqBodyPollOption{{StartTime: &start, EndTime: &end}, {StartTime: &start, EndTime: &end}}}, false},
+		{"no question", ReqBodyPoll{Question: "  ", Options: choices}, true},
+		{"unknown type", ReqBodyPoll{Question: "?", Type: "rank", Options: choices}, true},
+		{"closed already", ReqBodyPoll{Question: "?", ClosesAt: &past, Options: choices}, true},
+		{"one option", ReqBodyPoll{Question: "?", Options: choices[:1]}, true},
+		{"empty option", ReqBodyPoll{Question: "?", Options: []ReqBodyPollOption{{Label: "a"}, {Label: " "}}}, true},
+		{"date without times", ReqBodyPoll{Question: "?", Type: models.PollTypeDate, Options: choices}, true},
+		{"date ends first", ReqBodyPoll{Question: "?", Type: models.PollTypeDate, Options: []ReqBodyPollOption{{StartTime: &end, EndTime: &start}, {StartTime: &start, EndTime: &end}}}, true},
+		{"date in the past", ReqBodyPoll{Question: "?", Type: models.PollTypeDate, Options: []ReqBodyPollOption{{StartTime: &past, EndTime: &end}, {StartTime: &start, EndTime: &end}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePoll(&tt.reqBody, now); (err != nil) != tt.wantErr {
+				t.Errorf("validatePoll() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPollBallot(t *testing.T) {
+	options := []models.PollOption{{Id: 1}, {Id: 2}, {Id: 3}}
+	tests := []struct {
+		name     string
+		multiple bool
+		ballot   []uint
+		want     []uint
+		wantErr  bool
+	}{
+		{"single", false, []uint{2}, []uint{2}, false},
+		{"withdraw", false, nil, nil, false},
+		{"repeated", false, []uint{2, 2}, []uint{2}, false},
+		{"several", true, []uint{3, 1, 3}, []uint{3, 1}, false},
+		{"several on single choice", false, []uint{1, 2}, nil, true},
+		{"other poll", true, []uint{1, 4}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pollBallot(&models.EventPoll{MultipleChoice: tt.multiple, Options: options}, tt.ballot)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pollBallot(%v) = %v, want error %v", tt.ballot, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pollBallot(%v) = %v, want %v", tt.ballot, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPollWinner(t *testing.T) {
+	result := func(votes ...int) *PollResult {
+		var r PollResult
+		for i, v := range votes {
+			r.Results = append(r.Results, PollOptionResult{Option: models.PollOption{Id: uint(i + 1)}, Votes: v})
+		}
+		return &r
+	}
+	tests := []struct {
+		name     string
+		result   *PollResult
+		optionId uint
+		want     uint
+		wantErr  bool
+	}{
+		{"clear winner", result(1, 3, 2), 0, 2, false},
+		{"chosen winner", result(1, 3, 2), 2, 2, false},
+		{"no votes", result(0, 0), 0, 0, true},
+		{"tie", result(2, 2, 1), 0, 0, true},
+		{"tie broken", result(2, 2, 1), 1, 1, false},
+		{"not a winner", result(2, 2, 1), 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pollWinner(tt.result, tt.optionId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pollWinner(%d) = %v, want error %v", tt.optionId, err, tt.wantErr)
+			}
+			if err == nil && got.Id != tt.want {
+				t.Errorf("pollWinner(%d) = option %d, want %d", tt.optionId, got.Id, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+const (
+	maxReviewLength = 2000
+
+	// reputation is a bayesian average pulled towards reputationPrior until
+	// the organizer has collected about reputationWeight reviews
+	reputationPrior  = 3.0
+	reputationWeight = 5.0
+)
+
+type ReqBodyReview struct {
+	Rating  int    `json:"rating"`
+	Content string `json:"content"`
+}
+
+type RatingSummary struct {
+	Average      float64       `json:"average"`
+	Count        int64         `json:"count"`
+	Distribution map[int]int64 `json:"distribution"`
+}
+
+type OrganizerReputation struct {
+	Score       float64 `json:"score"`
+	Average     float64 `json:"average"`
+	Reviews     int64   `json:"reviews"`
+	RatedEvents int64   `json:"rated_events"`
+}
+
+func (uc *eventUsecase) CreateReviewUsecase(userId, eventId int, reqBody *ReqBodyReview) (*models.EventReview, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if reqBody.Rating < models.MinReviewRating || reqBody.Rating > models.MaxReviewRating {
+		return nil, fmt.Errorf("rating must be between %d and %d", models.MinReviewRating, models.MaxReviewRating)
+	}
+	reqBody.Content = strings.TrimSpace(reqBody.Content)
+	if len([]rune(reqBody.Content)) > maxReviewLength {
+		return nil, fmt.Errorf("review must be at most %d characters", maxReviewLength)
+	}
+
+	event, err := uc.visibleEvent(eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status == models.EventStatusDraft || event.Status == models.EventStatusCancelled {
+		return nil, fmt.Errorf("cannot review a %s event", event.Status)
+	}
+	if time.Now().Before(event.EndTime) {
+		return nil, fmt.Errorf("event can only be reviewed after it ends")
+	}
+
+	// organizers would only be rating themselves
+	role, err := uc.role(&event, userId)
+	if err != nil {
+		return nil, err
+	}
+	if role != "" {
+		return nil, fmt.Errorf("organizers cannot review their own event")
+	}
+
+	userEvent, err := uc.userEventRepo.GetUserFromEvent(userId, eventId)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, fmt.Errorf("only attendees can review this event")
+		}
+		return nil, err
+	}
+	if userEvent.JoinedAt != nil && !userEvent.JoinedAt.Before(event.EndTime) {
+		return nil, fmt.Errorf("only attendees can review this event")
+	}
+
+	_, err = uc.eventReviewRepo.GetReview(eventId, userId)
+	if err == nil {
+		return nil, fmt.Errorf("you have already reviewed this event")
+	}
+	if err.Error() != "record not found" {
+		return nil, err
+	}
+
+	return uc.eventReviewRepo.CreateReview(models.EventReview{
+		EventId: uint(eventId),
+		UserId:  uint(userId),
+		Rating:  reqBody.Rating,
+		Content: reqBody.Content,
+	})
+}
+
+func (uc *eventUsecase) GetReviewsUsecase(eventId, viewerId int, pagination *repositories.Pagination) ([]models.EventReview, *RatingSummary, *repositories.PageInfo, error) {
+	if eventId <= 0 {
+		return nil, nil, nil, fmt.Errorf("invalid event id")
+	}
+	pagination.Normalize()
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, nil, nil, err
+	}
+
+	summary, err := uc.ratingSummary(eventId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	reviews, pageInfo, err := uc.eventReviewRepo.GetReviews(eventId, pagination)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return reviews, summary, pageInfo, nil
+}
+
+func (uc *eventUsecase) ratingSummary(eventId int) (*RatingSummary, error) {
+	distribution, err := uc.eventReviewRepo.GetRatingDistribution(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := RatingSummary{Distribution: make(map[int]int64, models.MaxReviewRating)}
+	var sum int64
+	for rating := models.MinReviewRating; rating <= models.MaxReviewRating; rating++ {
+		summary.Distribution[rating] = distribution[rating]
+		summary.Count += distribution[rating]
+		sum += int64(rating) * distribution[rating]
+	}
+	if summary.Count > 0 {
+		summary.Average = roundRating(float64(sum) / float64(summary.Count))
+	}
+	return &summary, nil
+}
+
+// organizerReputations loads the reputation of several organizers in one
+// query. Organizers without reviews get the prior as their score.
+func (uc *eventUsecase) organizerReputations(userIds []uint64) (map[uint64]*OrganizerReputation, error) {
+	ratings, err := uc.eventReviewRepo.GetOrganizerRatings(userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	reputations := make(map[uint64]*OrganizerReputation, len(userIds))
+	for _, userId := range userIds {
+		reputations[userId] = &OrganizerReputation{Score: reputationPrior}
+	}
+	for _, rating := range ratings {
+		reputations[rating.UserId] = &OrganizerReputation{
+			Score:       roundRating((reputationPrior*reputationWeight + float64(rating.RatingSum)) / (reputationWeight + float64(rating.Reviews))),
+			Average:     roundRating(float64(rating.RatingSum) / float64(rating.Reviews)),
+			Reviews:     rating.Reviews,
+			RatedEvents: rating.RatedEvents,
+		}
+	}
+	return reputations, nil
+}
+
+func roundRating(value float64) float64 {
+	return math.Round(value*100) / 100
+}
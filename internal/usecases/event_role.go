@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"fmt"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// eventAuthorizer resolves a user's role on an event.
+type eventAuthorizer struct {
+	eventRepo     repositories.EventRepo
+	eventRoleRepo repositories.EventRoleRepo
+}
+
+// role returns the role of userId on event, or "" for users without one.
+func (a *eventAuthorizer) role(event *models.Event, userId int) (string, error) {
+	if event.CreatedBy == uint64(userId) {
+		return models.EventRoleOwner, nil
+	}
+	eventRole, err := a.eventRoleRepo.GetEventRole(int(event.Id), userId)
+	if err != nil && err.Error() != "record not found" {
+		return "", err
+	}
+	if eventRole == nil {
+		return "", nil
+	}
+	return eventRole.Role, nil
+}
+
+// authorize loads the event and checks that userId holds at least the minimum role.
+func (a *eventAuthorizer) authorize(eventId, userId int, minimum string) (models.Event, string, error) {
+	event, err := a.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return models.Event{}, "", err
+	}
+	role, err := a.role(&event, userId)
+	if err != nil {
+		return models.Event{}, "", err
+	}
+	if models.EventRoleRanks[role] < models.EventRoleRanks[minimum] {
+		return models.Event{}, "", fmt.Errorf("you don't have permission to do this on the event")
+	}
+	return event, role, nil
+}
+
+// visibleEvent loads an event the viewer may see. Drafts are seen by their
+// creator only, held or hidden events by their staff only.
+func (a *eventAuthorizer) visibleEvent(eventId, viewerId int) (models.Event, error) {
+	event, err := a.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if event.Status == models.EventStatusDraft && event.CreatedBy != uint64(viewerId) {
+		return models.Event{}, fmt.Errorf("record not found")
+	}
+	if event.HiddenAt != nil {
+		role, err := a.role(&event, viewerId)
+		if err != nil {
+			return models.Event{}, err
+		}
+		if role == "" {
+			return models.Event{}, fmt.Errorf("record not found")
+		}
+	}
+	return event, nil
+}
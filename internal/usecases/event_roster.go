@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+	"together-backend/pkg"
+)
+
+const (
+	RosterFormatCSV  = "csv"
+	RosterFormatXLSX = "xlsx"
+
+	RSVPGoing     = "going"
+	RSVPCheckedIn = "checked_in"
+	RSVPNoShow    = "no_show"
+	RSVPCancelled = "cancelled"
+
+	// rows between flushes so the client receives the export as it is built
+	rosterFlushEvery = 100
+)
+
+var rosterHeader = []string{"Name", "Email", "RSVP Status", "Joined At", "Checked In", "Checked In At"}
+
+// RosterExport is an authorized export; Write streams it once headers are sent.
+type RosterExport struct {
+	Filename    string
+	ContentType string
+	Write       func(w io.Writer) error
+}
+
+type rosterWriter interface {
+	Write(record []string) error
+	Flush() error
+}
+
+type csvRosterWriter struct {
+	*csv.Writer
+}
+
+func (c csvRosterWriter) Flush() error {
+	c.Writer.Flush()
+	return c.Writer.Error()
+}
+
+// Write prefixes cells that spreadsheets would run as formulas with an
+// apostrophe, since names and emails come from users.
+func (c csvRosterWriter) Write(record []string) error {
+	cells := make([]string, len(record))
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		cells[i] = cell
+	}
+	return c.Writer.Write(cells)
+}
+
+func (uc *eventUsecase) ExportAttendeesUsecase(userId, eventId int, format string) (*RosterExport, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if format == "" {
+		format = RosterFormatCSV
+	}
+
+	var contentType string
+	switch format {
+	case RosterFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case RosterFormatXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	event, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RosterExport{
+		Filename:    fmt.Sprintf("event-%d-attendees.%s", event.Id, format),
+		ContentType: contentType,
+		Write: func(w io.Writer) error {
+			if format == RosterFormatXLSX {
+				x, err := pkg.NewXLSXWriter(w, "Attendees")
+				if err != nil {
+					return err
+				}
+				if err := uc.writeRoster(x, &event); err != nil {
+					return err
+				}
+				return x.Close()
+			}
+			return uc.writeRoster(csvRosterWriter{csv.NewWriter(w)}, &event)
+		},
+	}, nil
+}
+
+func (uc *eventUsecase) writeRoster(rw rosterWriter, event *models.Event) error {
+	if err := rw.Write(rosterHeader); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	rows := 0
+	err := uc.userEventRepo.EachAttendee(int(event.Id), func(attendee *repositories.Attendee) error {
+		checkedIn := "no"
+		if attendee.CheckedInAt != nil {
+			checkedIn = "yes"
+		}
+		err := rw.Write([]string{
+			attendee.Name,
+			attendee.Email,
+			rsvpStatus(event, attendee, now),
+			formatRosterTime(attendee.JoinedAt),
+			checkedIn,
+			formatRosterTime(attendee.CheckedInAt),
+		})
+		if err != nil {
+			return err
+		}
+		rows++
+		if rows%rosterFlushEvery == 0 {
+			return rw.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return rw.Flush()
+}
+
+func rsvpStatus(event *models.Event, attendee *repositories.Attendee, now time.Time) string {
+	switch {
+	case attendee.CheckedInAt != nil:
+		return RSVPCheckedIn
+	case event.Status == models.EventStatusCancelled:
+		return RSVPCancelled
+	case now.After(event.EndTime):
+		return RSVPNoShow
+	}
+	return RSVPGoing
+}
+
+func formatRosterTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVRosterWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		cell string
+		want string
+	}{
+		{"plain", "Ada Lovelace", "Ada Lovelace"},
+		{"empty", "", ""},
+		{"inner formula", "a=1+1", "a=1+1"},
+		{"formula", "=HYPERLINK(\"x\")", "'=HYPERLINK(\"x\")"},
+		{"plus", "+1", "'+1"},
+		{"minus", "-1", "'-1"},
+		{"at", "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", "\t=1", "'\t=1"},
+		{"carriage return", "\r=1", "'\r=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := csvRosterWriter{csv.NewWriter(&buf)}
+			if err := w.Write([]string{tt.cell, "x"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			record, err := csv.NewReader(strings.NewReader(buf.String())).Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record[0] != tt.want || record[1] != "x" {
+				t.Errorf("Write(%q) wrote %q, want %q", tt.cell, record, []string{tt.want, "x"})
+			}
+		})
+	}
+}
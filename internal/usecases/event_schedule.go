@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+	"together-backend/internal/models"
+)
+
+const (
+	defaultScheduleRange = 30 * 24 * time.Hour
+	maxScheduleRange     = 366 * 24 * time.Hour
+
+	ScheduleRoleOrganizer = "organizer"
+	ScheduleRoleAttendee  = "attendee"
+)
+
+type ScheduleEntry struct {
+	EventId   uint      `json:"event_id"`
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Status    string    `json:"status"`
+	Role      string    `json:"role"`
+}
+
+// ScheduleBlock is a stretch of time covered by one or more events; the
+// events in a block with Conflict set overlap each other.
+type ScheduleBlock struct {
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	Conflict  bool            `json:"conflict"`
+	Events    []ScheduleEntry `json:"events"`
+}
+
+type JoinWarning struct {
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	Conflicts []ScheduleEntry `json:"conflicts"`
+}
+
+// ScheduleConflictError rejects a strict join that overlaps other events.
+type ScheduleConflictError struct {
+	Conflicts []ScheduleEntry
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("event overlaps %d of your other events", len(e.Conflicts))
+}
+
+func (uc *eventUsecase) GetUserScheduleUsecase(userId, viewerId int, from, to *time.Time) ([]ScheduleBlock, error) {
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	start := time.Now()
+	if from != nil {
+		start = *from
+	}
+	end := start.Add(defaultScheduleRange)
+	if to != nil {
+		end = *to
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("to must be greater than from")
+	}
+	if end.Sub(start) > maxScheduleRange {
+		return nil, fmt.Errorf("schedule range must be at most %d days", int(maxScheduleRange.Hours()/24))
+	}
+
+	events, err := uc.eventRepo.GetUserSchedule(userId, start, end, userId == viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := []ScheduleBlock{}
+	for _, event := range events {
+		entry := scheduleEntry(&event, userId)
+		last := len(blocks) - 1
+		if last >= 0 && entry.StartTime.Before(blocks[last].EndTime) {
+			blocks[last].Conflict = true
+			blocks[last].Events = append(blocks[last].Events, entry)
+			if entry.EndTime.After(blocks[last].EndTime) {
+				blocks[last].EndTime = entry.EndTime
+			}
+			continue
+		}
+		blocks = append(blocks, ScheduleBlock{
+			StartTime: entry.StartTime,
+			EndTime:   entry.EndTime,
+			Events:    []ScheduleEntry{entry},
+		})
+	}
+	return blocks, nil
+}
+
+// scheduleConflicts lists the user's other events overlapping event.
+func (uc *eventUsecase) scheduleConflicts(userId int, event *models.Event) ([]ScheduleEntry, error) {
+	events, err := uc.eventRepo.GetUserSchedule(userId, event.StartTime, event.EndTime, true)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []ScheduleEntry{}
+	for i := range events {
+		if events[i].Id != event.Id {
+			conflicts = append(conflicts, scheduleEntry(&events[i], userId))
+		}
+	}
+	return conflicts, nil
+}
+
+func scheduleEntry(event *models.Event, userId int) ScheduleEntry {
+	role := ScheduleRoleAttendee
+	if event.CreatedBy == uint64(userId) {
+		role = ScheduleRoleOrganizer
+	}
+	return ScheduleEntry{
+		EventId:   event.Id,
+		Title:     event.Title,
+		StartTime: event.StartTime,
+		EndTime:   event.EndTime,
+		Status:    event.Status,
+		Role:      role,
+	}
+}
@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+type EventSessionUseCase interface {
+	GetSessionsUsecase(eventId, viewerId int) ([]models.EventSession, error)
+	CreateSessionUsecase(userId, eventId int, reqBody *ReqBodySession) (*models.EventSession, error)
+	UpdateSessionUsecase(userId, eventId, sessionId int, reqBody *ReqBodySession) (*models.EventSession, error)
+	DeleteSessionUsecase(userId, eventId, sessionId int) (*models.EventSession, error)
+	SignupUsecase(userId, eventId, sessionId int) (*models.SessionSignup, error)
+	CancelSignupUsecase(userId, eventId, sessionId int) (*models.SessionSignup, error)
+}
+
+type eventSessionUsecase struct {
+	eventAuthorizer
+	eventSessionRepo repositories.EventSessionRepo
+	userEventRepo    repositories.UserEventRepo
+	uow              repositories.UnitOfWork
+}
+
+type ReqBodySession struct {
+	Title     string    `json:"title"`
+	Speaker   string    `json:"speaker"`
+	Room      string    `json:"room"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Capacity  int       `json:"capacity"`
+}
+
+func NewEventSessionUsecase(eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo, eventSessionRepo repositories.EventSessionRepo, userEventRepo repositories.UserEventRepo, uow repositories.UnitOfWork) EventSessionUseCase {
+	return &eventSessionUsecase{
+		eventAuthorizer:  eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		eventSessionRepo: eventSessionRepo,
+		userEventRepo:    userEventRepo,
+		uow:              uow,
+	}
+}
+
+func (uc *eventSessionUsecase) GetSessionsUsecase(eventId, viewerId int) ([]models.EventSession, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	if _, err := uc.visibleEvent(eventId, viewerId); err != nil {
+		return nil, err
+	}
+
+	return uc.eventSessionRepo.GetSessions(eventId, viewerId)
+}
+
+func (uc *eventSessionUsecase) CreateSessionUsecase(userId, eventId int, reqBody *ReqBodySession) (*models.EventSession, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	event, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSession(&event, reqBody); err != nil {
+		return nil, err
+	}
+
+	return uc.eventSessionRepo.CreateSession(models.EventSession{
+		EventId:   event.Id,
+		Title:     reqBody.Title,
+		Speaker:   reqBody.Speaker,
+		Room:      reqBody.Room,
+		StartTime: reqBody.StartTime,
+		EndTime:   reqBody.EndTime,
+		Capacity:  reqBody.Capacity,
+	})
+}
+
+func (uc *eventSessionUsecase) UpdateSessionUsecase(userId, eventId, sessionId int, reqBody *ReqBodySession) (*models.EventSession, error) {
+	if eventId <= 0 || sessionId <= 0 {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	var session *models.EventSession
+	err := uc.inTransaction(func(tx *eventSessionUsecase) error {
+		event, _, err := tx.authorize(eventId, userId, models.EventRoleCoOrganizer)
+		if err != nil {
+			return err
+		}
+		if err := validateSession(&event, reqBody); err != nil {
+			return err
+		}
+
+		session, err = tx.eventSessionRepo.GetSession(sessionId, eventId)
+		if err != nil {
+			return err
+		}
+
+		signups, err := tx.eventSessionRepo.CountSignups(sessionId)
+		if err != nil {
+			return err
+		}
+		if reqBody.Capacity > 0 && int64(reqBody.Capacity) < signups {
+			return fmt.Errorf("capacity cannot be less than the %d users already signed up", signups)
+		}
+		if !reqBody.StartTime.Equal(session.StartTime) || !reqBody.EndTime.Equal(session.EndTime) {
+			clashes, err := tx.eventSessionRepo.CountOverlappingSignups(sessionId, reqBody.StartTime, reqBody.EndTime)
+			if err != nil {
+				return err
+			}
+			if clashes > 0 {
+				return fmt.Errorf("new times overlap other sessions of %d signed up users", clashes)
+			}
+		}
+
+		session.Title = reqBody.Title
+		session.Speaker = reqBody.Speaker
+		session.Room = reqBody.Room
+		session.StartTime = reqBody.StartTime
+		session.EndTime = reqBody.EndTime
+		session.Capacity = reqBody.Capacity
+		session, err = tx.eventSessionRepo.UpdateSession(session)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (uc *eventSessionUsecase) DeleteSessionUsecase(userId, eventId, sessionId int) (*models.EventSession, error) {
+	if eventId <= 0 || sessionId <= 0 {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	_, _, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.eventSessionRepo.GetSession(sessionId, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.eventSessionRepo.DeleteSession(session)
+}
+
+// SignupUsecase reserves a seat in a session. The capacity check and the
+// insert share one serializable transaction so the last seat is taken once.
+func (uc *eventSessionUsecase) SignupUsecase(userId, eventId, sessionId int) (*models.SessionSignup, error) {
+	if eventId <= 0 || sessionId <= 0 {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	var signup *models.SessionSignup
+	err := uc.inTransaction(func(tx *eventSessionUsecase) error {
+		event, err := tx.visibleEvent(eventId, userId)
+		if err != nil {
+			return err
+		}
+		if event.Status != models.EventStatusPublished && event.Status != models.EventStatusPostponed {
+			return fmt.Errorf("cannot sign up for sessions of a %s event", event.Status)
+		}
+
+		_, err = tx.userEventRepo.GetUserFromEvent(userId, eventId)
+		if err != nil {
+			if err.Error() == "record not found" {
+				return fmt.Errorf("join the event before signing up for its sessions")
+			}
+			return err
+		}
+
+		session, err := tx.eventSessionRepo.GetSession(sessionId, eventId)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(session.EndTime) {
+			return fmt.Errorf("session has already ended")
+		}
+
+		_, err = tx.eventSessionRepo.GetSignup(sessionId, userId)
+		if err == nil {
+			return fmt.Errorf("you have already signed up for this session")
+		}
+		if err.Error() != "record not found" {
+			return err
+		}
+
+		if session.Capacity > 0 {
+			signups, err := tx.eventSessionRepo.CountSignups(sessionId)
+			if err != nil {
+				return err
+			}
+			if signups >= int64(session.Capacity) {
+				return fmt.Errorf("session is full")
+			}
+		}
+
+		overlapping, err := tx.eventSessionRepo.GetOverlappingSignups(userId, session.StartTime, session.EndTime, sessionId)
+		if err != nil {
+			return err
+		}
+		if len(overlapping) > 0 {
+			titles := make([]string, len(overlapping))
+			for i := range overlapping {
+				titles[i] = overlapping[i].Title
+			}
+			return fmt.Errorf("session overlaps your other sessions: %s", strings.Join(titles, ", "))
+		}
+
+		signup, err = tx.eventSessionRepo.AddSignup(sessionId, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signup, nil
+}
+
+func (uc *eventSessionUsecase) CancelSignupUsecase(userId, eventId, sessionId int) (*models.SessionSignup, error) {
+	if eventId <= 0 || sessionId <= 0 {
+		return nil, fmt.Errorf("invalid session id")
+	}
+
+	if _, err := uc.eventSessionRepo.GetSession(sessionId, eventId); err != nil {
+		return nil, err
+	}
+	if _, err := uc.eventSessionRepo.GetSignup(sessionId, userId); err != nil {
+		if err.Error() == "record not found" {
+			return nil, fmt.Errorf("you haven't signed up for this session")
+		}
+		return nil, err
+	}
+
+	return uc.eventSessionRepo.RemoveSignup(sessionId, userId)
+}
+
+func (uc *eventSessionUsecase) inTransaction(fn func(tx *eventSessionUsecase) error) error {
+	return inTransaction(uc.uow, uc, func(tx transactional) error {
+		return fn(tx.(*eventSessionUsecase))
+	})
+}
+
+func (uc *eventSessionUsecase) withRepositories(repos *repositories.Repositories) transactional {
+	return &eventSessionUsecase{
+		eventAuthorizer:  eventAuthorizer{eventRepo: repos.Events, eventRoleRepo: repos.EventRoles},
+		eventSessionRepo: repos.EventSessions,
+		userEventRepo:    repos.UserEvents,
+	}
+}
+
+func validateSession(event *models.Event, reqBody *ReqBodySession) error {
+	reqBody.Title = strings.TrimSpace(reqBody.Title)
+	if reqBody.Title == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+	if !reqBody.StartTime.Before(reqBody.EndTime) {
+		return fmt.Errorf("start time must be less than end time")
+	}
+	if reqBody.StartTime.Before(event.StartTime) || reqBody.EndTime.After(event.EndTime) {
+		return fmt.Errorf("session must be within the event from %s to %s", event.StartTime.Format(time.RFC3339), event.EndTime.Format(time.RFC3339))
+	}
+	if reqBody.Capacity < 0 {
+		return fmt.Errorf("capacity cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"fmt"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+type EventStaffUseCase interface {
+	GetStaffUsecase(eventId, viewerId int) ([]models.EventRole, error)
+	AddStaffUsecase(userId, eventId int, reqBody *ReqBodyStaff) (*models.EventRole, error)
+	RemoveStaffUsecase(userId, eventId, staffId int) (*models.EventRole, error)
+	TransferOwnershipUsecase(userId, eventId, newOwnerId int) (*models.Event, error)
+}
+
+type eventStaffUsecase struct {
+	eventAuthorizer
+	userRepo         repositories.UserRepo
+	notificationRepo repositories.NotificationRepo
+}
+
+type ReqBodyStaff struct {
+	UserId int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func NewEventStaffUsecase(eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo, userRepo repositories.UserRepo, notificationRepo repositories.NotificationRepo) EventStaffUseCase {
+	return &eventStaffUsecase{
+		eventAuthorizer:  eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (uc *eventStaffUsecase) GetStaffUsecase(eventId, viewerId int) ([]models.EventRole, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	event, err := uc.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := uc.userRepo.GetUserById(int64(event.CreatedBy))
+	if err != nil {
+		return nil, err
+	}
+
+	staff, err := uc.eventRoleRepo.GetEventRoles(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]models.EventRole{{
+		EventId:   event.Id,
+		UserId:    owner.Id,
+		User:      owner,
+		Role:      models.EventRoleOwner,
+		CreatedAt: event.CreatedAt,
+	}}, staff...), nil
+}
+
+func (uc *eventStaffUsecase) AddStaffUsecase(userId, eventId int, reqBody *ReqBodyStaff) (*models.EventRole, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if reqBody.UserId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if reqBody.Role != models.EventRoleCoOrganizer && reqBody.Role != models.EventRoleModerator {
+		return nil, fmt.Errorf("role must be co_organizer or moderator")
+	}
+
+	event, role, err := uc.authorize(eventId, userId, models.EventRoleCoOrganizer)
+	if err != nil {
+		return nil, err
+	}
+	if models.EventRoleRanks[role] <= models.EventRoleRanks[reqBody.Role] {
+		return nil, fmt.Errorf("you can only add staff with a lower role than yours")
+	}
+	if event.CreatedBy == uint64(reqBody.UserId) {
+		return nil, fmt.Errorf("user is already the owner of the event")
+	}
+
+	current, err := uc.role(&event, reqBody.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if models.EventRoleRanks[current] >= models.EventRoleRanks[role] {
+		return nil, fmt.Errorf("you can't change the role of this user")
+	}
+
+	if _, err := uc.userRepo.GetUserById(int64(reqBody.UserId)); err != nil {
+		return nil, err
+	}
+
+	eventRole, err := uc.eventRoleRepo.SaveEventRole(eventId, reqBody.UserId, reqBody.Role, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.notificationRepo.CreateNotifications([]models.Notification{{
+		UserId:  uint(reqBody.UserId),
+		EventId: event.Id,
+		Type:    models.NotificationEventStaff,
+		Message: fmt.Sprintf("You are now %s of event \"%s\"", reqBody.Role, event.Title),
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return eventRole, nil
+}
+
+func (uc *eventStaffUsecase) RemoveStaffUsecase(userId, eventId, staffId int) (*models.EventRole, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if staffId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	event, err := uc.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return nil, err
+	}
+	staffRole, err := uc.role(&event, staffId)
+	if err != nil {
+		return nil, err
+	}
+	if staffRole == "" {
+		return nil, fmt.Errorf("user is not a staff member of the event")
+	}
+	if staffRole == models.EventRoleOwner {
+		return nil, fmt.Errorf("the owner can't be removed, transfer the ownership instead")
+	}
+
+	// staff can always step down themselves
+	if userId != staffId {
+		role, err := uc.role(&event, userId)
+		if err != nil {
+			return nil, err
+		}
+		if models.EventRoleRanks[role] <= models.EventRoleRanks[staffRole] {
+			return nil, fmt.Errorf("you don't have permission to remove this user")
+		}
+	}
+
+	return uc.eventRoleRepo.RemoveEventRole(eventId, staffId)
+}
+
+func (uc *eventStaffUsecase) TransferOwnershipUsecase(userId, eventId, newOwnerId int) (*models.Event, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if newOwnerId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if userId == newOwnerId {
+		return nil, fmt.Errorf("you are already the owner of the event")
+	}
+
+	event, _, err := uc.authorize(eventId, userId, models.EventRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.userRepo.GetUserById(int64(newOwnerId)); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.eventRoleRepo.TransferOwnership(event, newOwnerId); err != nil {
+		return nil, err
+	}
+
+	err = uc.notificationRepo.CreateNotifications([]models.Notification{{
+		UserId:  uint(newOwnerId),
+		EventId: event.Id,
+		Type:    models.NotificationEventStaff,
+		Message: fmt.Sprintf("You are now the owner of event \"%s\"", event.Title),
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedEvent, err := uc.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return nil, err
+	}
+	return &updatedEvent, nil
+}
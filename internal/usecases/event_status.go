@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+	"together-backend/internal/models"
+)
+
+type ReqBodyEventStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func canTransition(from, to string) bool {
+	for _, status := range models.EventStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *eventUsecase) ChangeEventStatusUsecase(userId, eventId int, reqBody *ReqBodyEventStatus) (*EventsCreatedByUser, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if _, ok := models.EventStatusTransitions[reqBody.Status]; !ok {
+		return nil, fmt.Errorf("invalid status %s", reqBody.Status)
+	}
+	if (reqBody.Status == models.EventStatusCancelled || reqBody.Status == models.EventStatusPostponed) && reqBody.Reason == "" {
+		return nil, fmt.Errorf("reason cannot be empty when the event is %s", reqBody.Status)
+	}
+
+	// the status and its notifications commit together
+	err := uc.inTransaction(func(tx *eventUsecase) error {
+		event, _, err := tx.authorize(eventId, userId, models.EventRoleCoOrganizer)
+		if err != nil {
+			return err
+		}
+		if !canTransition(event.Status, reqBody.Status) {
+			return fmt.Errorf("cannot change status from %s to %s", event.Status, reqBody.Status)
+		}
+		if reqBody.Status == models.EventStatusCompleted && time.Now().Before(event.StartTime) {
+			return fmt.Errorf("event has not started yet")
+		}
+
+		if _, err := tx.eventRepo.UpdateEventStatus(event, reqBody.Status, reqBody.Reason); err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("Event \"%s\" is now %s", event.Title, reqBody.Status)
+		if reqBody.Reason != "" {
+			message += ": " + reqBody.Reason
+		}
+		return tx.notifyAttendees(eventId, userId, models.NotificationEventStatus, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetEventDetailUsecase(eventId, userId)
+}
+
+// PublishDueEventsUsecase publishes the drafts whose publish time has come.
+// The events and their notifications commit together, so a failed insert
+// leaves the events due for the next run.
+func (uc *eventUsecase) PublishDueEventsUsecase(now time.Time) (int, error) {
+	var published int
+	err := uc.inTransaction(func(tx *eventUsecase) error {
+		events, err := tx.eventRepo.PublishDueEvents(now)
+		if err != nil {
+			return err
+		}
+
+		var notifications []models.Notification
+		for _, event := range events {
+			notifications = append(notifications, models.Notification{
+				UserId:  uint(event.CreatedBy),
+				EventId: event.Id,
+				Type:    models.NotificationEventStatus,
+				Message: fmt.Sprintf("Event \"%s\" has been published", event.Title),
+			})
+		}
+		if err := tx.notificationRepo.CreateNotifications(notifications); err != nil {
+			return err
+		}
+
+		published = len(events)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, nil
+}
+
+// notifyAttendees sends a notification to every attendee of the event except excludeUserId.
+func (uc *eventUsecase) notifyAttendees(eventId, excludeUserId int, notificationType, message string) error {
+	userIds, err := uc.userEventRepo.GetUserIdsByEventId(eventId)
+	if err != nil {
+		return err
+	}
+
+	var notifications []models.Notification
+	for _, userId := range userIds {
+		if int(userId) == excludeUserId {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserId:  userId,
+			EventId: uint(eventId),
+			Type:    notificationType,
+			Message: message,
+		})
+	}
+
+	return uc.notificationRepo.CreateNotifications(notifications)
+}
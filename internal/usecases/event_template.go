@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+type EventTemplateUseCase interface {
+	GetTemplatesUsecase(userId int) ([]models.EventTemplate, error)
+	CreateTemplateUsecase(userId int, reqBody *ReqBodyEventTemplate) (*models.EventTemplate, error)
+	DeleteTemplateUsecase(userId, templateId int) (*models.EventTemplate, error)
+	ApplyTemplateUsecase(userId, templateId int, reqBody *ReqBodyEvent) error
+}
+
+type eventTemplateUsecase struct {
+	eventAuthorizer
+	eventTemplateRepo repositories.EventTemplateRepo
+}
+
+// ReqBodyEventTemplate saves a template from the given fields, or from an
+// existing event when EventId is set.
+type ReqBodyEventTemplate struct {
+	Name           string `json:"name"`
+	EventId        int    `json:"event_id"`
+	Title          string `json:"title"`
+	Content        string `json:"content"`
+	Duration       int    `json:"duration"`
+	Location       int    `json:"location"`
+	DetailLocation string `json:"detail_location"`
+}
+
+func NewEventTemplateUsecase(eventTemplateRepo repositories.EventTemplateRepo, eventRepo repositories.EventRepo, eventRoleRepo repositories.EventRoleRepo) EventTemplateUseCase {
+	return &eventTemplateUsecase{
+		eventAuthorizer:   eventAuthorizer{eventRepo: eventRepo, eventRoleRepo: eventRoleRepo},
+		eventTemplateRepo: eventTemplateRepo,
+	}
+}
+
+func (uc *eventTemplateUsecase) GetTemplatesUsecase(userId int) ([]models.EventTemplate, error) {
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	return uc.eventTemplateRepo.GetTemplates(userId)
+}
+
+func (uc *eventTemplateUsecase) CreateTemplateUsecase(userId int, reqBody *ReqBodyEventTemplate) (*models.EventTemplate, error) {
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if reqBody.Name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+	if reqBody.Duration < 0 {
+		return nil, fmt.Errorf("duration cannot be negative")
+	}
+
+	template := models.EventTemplate{
+		UserId:         uint(userId),
+		Name:           reqBody.Name,
+		Title:          reqBody.Title,
+		Content:        reqBody.Content,
+		Duration:       reqBody.Duration,
+		Location:       reqBody.Location,
+		DetailLocation: reqBody.DetailLocation,
+	}
+	if reqBody.EventId != 0 {
+		event, _, err := uc.authorize(reqBody.EventId, userId, models.EventRoleCoOrganizer)
+		if err != nil {
+			return nil, err
+		}
+		template.Title = event.Title
+		template.Content = event.Content
+		template.Duration = int(event.EndTime.Sub(event.StartTime).Minutes())
+		template.Location = event.Location
+		template.DetailLocation = event.DetailLocation
+	}
+
+	return uc.eventTemplateRepo.CreateTemplate(template)
+}
+
+func (uc *eventTemplateUsecase) DeleteTemplateUsecase(userId, templateId int) (*models.EventTemplate, error) {
+	if userId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	if templateId <= 0 {
+		return nil, fmt.Errorf("invalid template id")
+	}
+
+	template, err := uc.eventTemplateRepo.GetTemplate(templateId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.eventTemplateRepo.DeleteTemplate(template)
+}
+
+// ApplyTemplateUsecase fills the fields of reqBody the client left empty from the template.
+func (uc *eventTemplateUsecase) ApplyTemplateUsecase(userId, templateId int, reqBody *ReqBodyEvent) error {
+	template, err := uc.eventTemplateRepo.GetTemplate(templateId, userId)
+	if err != nil {
+		return err
+	}
+
+	if reqBody.Title == "" {
+		reqBody.Title = template.Title
+	}
+	if reqBody.Content == "" {
+		reqBody.Content = template.Content
+	}
+	if reqBody.Location == 0 {
+		reqBody.Location = template.Location
+	}
+	if reqBody.DetailLocation == "" {
+		reqBody.DetailLocation = template.DetailLocation
+	}
+	if reqBody.EndTime.IsZero() && !reqBody.StartTime.IsZero() {
+		reqBody.EndTime = reqBody.StartTime.Add(time.Duration(template.Duration) * time.Minute)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// The fakes below embed the repository interfaces and implement only what
+// the tests reach, any other call panics on the nil interface.
+
+type fakeUserRepo struct {
+	repositories.UserRepo
+	users map[int64]models.User
+}
+
+func (r *fakeUserRepo) GetUserById(id int64) (models.User, error) {
+	return r.users[id], nil
+}
+
+type fakeCommentRepo struct {
+	repositories.CommentRepo
+	comments map[int]*models.Comment
+	posted   int64
+}
+
+func (r *fakeCommentRepo) GetEventComment(commentId, eventId int) (*models.Comment, error) {
+	return r.comments[commentId], nil
+}
+
+func (r *fakeCommentRepo) CountUserCommentsSince(userId int, since time.Time) (int64, error) {
+	return r.posted, nil
+}
+
+type fakeEventRepo struct {
+	repositories.EventRepo
+	events []*models.Event
+	posted int64
+}
+
+func (r *fakeEventRepo) CountUserEventsSince(userId int, since time.Time) (int64, error) {
+	return r.posted, nil
+}
+
+func (r *fakeEventRepo) CreateEvent(title, content string, imageUrl []string, createdBy uint64, startTime, endTime time.Time, location int, detailLocation, status string, publishAt *time.Time) (*models.Event, error) {
+	event := &models.Event{
+		Id:        uint(len(r.events) + 1),
+		Title:     title,
+		Content:   content,
+		CreatedBy: createdBy,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Status:    status,
+		PublishAt: publishAt,
+	}
+	r.events = append(r.events, event)
+	return event, nil
+}
+
+// fakeModerationRepo keeps one item per target and the users who reported
+// each item.
+type fakeModerationRepo struct {
+	repositories.ModerationRepo
+	items     []*models.ModerationItem
+	reporters map[uint][]int
+	held      []*models.ModerationItem
+	hidden    []uint
+}
+
+func (r *fakeModerationRepo) HoldContent(item *models.ModerationItem, hiddenAt time.Time) (*models.ModerationItem, error) {
+	r.held = append(r.held, item)
+	return item, nil
+}
+
+func (r *fakeModerationRepo) GetOpenItem(targetType string, targetId uint) (*models.ModerationItem, error) {
+	for _, item := range r.items {
+		if item.TargetType == targetType && item.TargetId == targetId {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeModerationRepo) CreateItem(item *models.ModerationItem) (*models.ModerationItem, error) {
+	item.Id = uint(len(r.items) + 1)
+	r.items = append(r.items, item)
+	return item, nil
+}
+
+func (r *fakeModerationRepo) UpdateItem(item *models.ModerationItem, updates map[string]interface{}) (*models.ModerationItem, error) {
+	if hidden, ok := updates["target_hidden"].(bool); ok {
+		item.TargetHidden = hidden
+	}
+	return item, nil
+}
+
+func (r *fakeModerationRepo) CreateReport(report *models.ModerationReport) (*models.ModerationReport, error) {
+	if r.reporters == nil {
+		r.reporters = make(map[uint][]int)
+	}
+	r.reporters[report.ItemId] = append(r.reporters[report.ItemId], int(report.ReporterId))
+	return report, nil
+}
+
+func (r *fakeModerationRepo) HasReported(itemId uint, reporterId int) (bool, error) {
+	for _, id := range r.reporters[itemId] {
+		if id == reporterId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeModerationRepo) CountReports(itemId uint) (int64, error) {
+	return int64(len(r.reporters[itemId])), nil
+}
+
+func (r *fakeModerationRepo) SetTargetHidden(targetType string, targetId uint, hiddenAt *time.Time) (bool, error) {
+	r.hidden = append(r.hidden, targetId)
+	return true, nil
+}
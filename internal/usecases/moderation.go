@@ -0,0 +1,366 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+// DefaultAutoHideReports is how many distinct reporters hide an item's
+// target until a moderator looks at it.
+const DefaultAutoHideReports = 3
+
+const maxReportDetails = 1000
+
+type ModerationUseCase interface {
+	ReportEventUsecase(reqBody *ReqBodyReport, eventId, userId int) (*models.ModerationItem, error)
+	ReportCommentUsecase(reqBody *ReqBodyReport, commentId, eventId, userId int) (*models.ModerationItem, error)
+	ReportUserUsecase(reqBody *ReqBodyReport, reportedId, userId int) (*models.ModerationItem, error)
+	GetQueueUsecase(userId int, filter *repositories.ModerationFilter, pagination *repositories.Pagination) ([]models.ModerationItem, *repositories.PageInfo, error)
+	GetQueueItemUsecase(itemId, userId int) (*models.ModerationItem, error)
+	AssignItemUsecase(reqBody *ReqBodyAssignItem, itemId, userId int) (*models.ModerationItem, error)
+	ResolveItemUsecase(reqBody *ReqBodyResolveItem, itemId, userId int) (*models.ModerationItem, error)
+}
+
+type moderationUsecase struct {
+	moderationRepo  repositories.ModerationRepo
+	eventRepo       repositories.EventRepo
+	commentRepo     repositories.CommentRepo
+	userRepo        repositories.UserRepo
+	uow             repositories.UnitOfWork
+	autoHideReports int
+}
+
+type ReqBodyReport struct {
+	Category string `json:"category"`
+	Details  string `json:"details"`
+}
+
+type ReqBodyAssignItem struct {
+	// AssigneeId defaults to the moderator making the request.
+	AssigneeId uint `json:"assignee_id"`
+}
+
+type ReqBodyResolveItem struct {
+	Resolution string `json:"resolution"`
+	Note       string `json:"note"`
+	// SuspendDays bounds a suspension, 0 suspends until lifted.
+	SuspendDays int `json:"suspend_days"`
+}
+
+func NewModerationUsecase(moderationRepo repositories.ModerationRepo, eventRepo repositories.EventRepo, commentRepo repositories.CommentRepo, userRepo repositories.UserRepo, uow repositories.UnitOfWork, autoHideReports int) ModerationUseCase {
+	return &moderationUsecase{
+		moderationRepo:  moderationRepo,
+		eventRepo:       eventRepo,
+		commentRepo:     commentRepo,
+		userRepo:        userRepo,
+		uow:             uow,
+		autoHideReports: autoHideReports,
+	}
+}
+
+func (uc *moderationUsecase) inTransaction(fn func(tx *moderationUsecase) error) error {
+	return inTransaction(uc.uow, uc, func(tx transactional) error {
+		return fn(tx.(*moderationUsecase))
+	})
+}
+
+func (uc *moderationUsecase) withRepositories(repos *repositories.Repositories) transactional {
+	return &moderationUsecase{
+		moderationRepo:  repos.Moderation,
+		eventRepo:       repos.Events,
+		commentRepo:     repos.Comments,
+		userRepo:        repos.Users,
+		autoHideReports: uc.autoHideReports,
+	}
+}
+
+func (uc *moderationUsecase) ReportEventUsecase(reqBody *ReqBodyReport, eventId, userId int) (*models.ModerationItem, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	event, err := uc.eventRepo.GetEventDetail(eventId)
+	if err != nil {
+		return nil, err
+	}
+	if (event.Status == models.EventStatusDraft || event.HiddenAt != nil) && event.CreatedBy != uint64(userId) {
+		return nil, fmt.Errorf("record not found")
+	}
+
+	return uc.report(&models.ModerationItem{
+		TargetType: models.ModerationTargetEvent,
+		TargetId:   event.Id,
+		EventId:    &event.Id,
+		AuthorId:   uint(event.CreatedBy),
+	}, reqBody, userId)
+}
+
+func (uc *moderationUsecase) ReportCommentUsecase(reqBody *ReqBodyReport, commentId, eventId, userId int) (*models.ModerationItem, error) {
+	if eventId <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+	if commentId <= 0 {
+		return nil, fmt.Errorf("invalid comment id")
+	}
+
+	comment, err := uc.commentRepo.GetEventComment(commentId, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.report(&models.ModerationItem{
+		TargetType: models.ModerationTargetComment,
+		TargetId:   comment.Id,
+		EventId:    &comment.EventId,
+		AuthorId:   comment.UserId,
+	}, reqBody, userId)
+}
+
+func (uc *moderationUsecase) ReportUserUsecase(reqBody *ReqBodyReport, reportedId, userId int) (*models.ModerationItem, error) {
+	if reportedId <= 0 {
+		return nil, fmt.Errorf("invalid user id")
+	}
+
+	reported, err := uc.userRepo.GetUserById(int64(reportedId))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.report(&models.ModerationItem{
+		TargetType: models.ModerationTargetUser,
+		TargetId:   reported.Id,
+		AuthorId:   reported.Id,
+	}, reqBody, userId)
+}
+
+// report files the user's report on the open item of the target, opening
+// one if needed, and hides the target once enough distinct users reported
+// it.
+func (uc *moderationUsecase) report(target *models.ModerationItem, reqBody *ReqBodyReport, userId int) (*models.ModerationItem, error) {
+	if !models.IsReportCategory(reqBody.Category) {
+		return nil, fmt.Errorf("category must be one of %s", strings.Join(models.ReportCategories, ", "))
+	}
+	details := strings.TrimSpace(reqBody.Details)
+	if len([]rune(details)) > maxReportDetails {
+		return nil, fmt.Errorf("details must be at most %d characters", maxReportDetails)
+	}
+	if target.AuthorId == uint(userId) {
+		return nil, fmt.Errorf("you can't report yourself")
+	}
+
+	var item *models.ModerationItem
+	err := uc.inTransaction(func(tx *moderationUsecase) error {
+		var err error
+		item, err = tx.moderationRepo.GetOpenItem(target.TargetType, target.TargetId)
+		if err != nil && err.Error() != "record not found" {
+			return err
+		}
+		if item == nil {
+			target.Source = models.ModerationSourceReport
+			target.Reason = "reported by users"
+			if item, err = tx.moderationRepo.CreateItem(target); err != nil {
+				return err
+			}
+		}
+
+		reported, err := tx.moderationRepo.HasReported(item.Id, userId)
+		if err != nil {
+			return err
+		}
+		if reported {
+			return fmt.Errorf("you already reported this")
+		}
+		_, err = tx.moderationRepo.CreateReport(&models.ModerationReport{
+			ItemId:     item.Id,
+			ReporterId: uint(userId),
+			Category:   reqBody.Category,
+			Details:    details,
+		})
+		if err != nil {
+			return err
+		}
+
+		item.ReportCount, err = tx.moderationRepo.CountReports(item.Id)
+		if err != nil {
+			return err
+		}
+		if uc.autoHideReports <= 0 || item.ReportCount < int64(uc.autoHideReports) || item.TargetHidden || item.TargetType == models.ModerationTargetUser {
+			return nil
+		}
+		now := time.Now()
+		hidden, err := tx.moderationRepo.SetTargetHidden(item.TargetType, item.TargetId, &now)
+		if err != nil || !hidden {
+			return err
+		}
+		_, err = tx.moderationRepo.UpdateItem(item, map[string]interface{}{"target_hidden": true})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// requireModerator lets only site moderators work the queue.
+func (uc *moderationUsecase) requireModerator(userId int) error {
+	user, err := uc.userRepo.GetUserById(int64(userId))
+	if err != nil {
+		return err
+	}
+	if user.Role != models.UserRoleModerator {
+		return fmt.Errorf("only moderators can access the moderation queue")
+	}
+	return nil
+}
+
+func (uc *moderationUsecase) GetQueueUsecase(userId int, filter *repositories.ModerationFilter, pagination *repositories.Pagination) ([]models.ModerationItem, *repositories.PageInfo, error) {
+	if err := uc.requireModerator(userId); err != nil {
+		return nil, nil, err
+	}
+	if filter.Category != "" && !models.IsReportCategory(filter.Category) {
+		return nil, nil, fmt.Errorf("category must be one of %s", strings.Join(models.ReportCategories, ", "))
+	}
+	pagination.Normalize()
+
+	return uc.moderationRepo.GetItems(filter, pagination)
+}
+
+func (uc *moderationUsecase) GetQueueItemUsecase(itemId, userId int) (*models.ModerationItem, error) {
+	if itemId <= 0 {
+		return nil, fmt.Errorf("invalid item id")
+	}
+	if err := uc.requireModerator(userId); err != nil {
+		return nil, err
+	}
+
+	return uc.moderationRepo.GetItem(itemId)
+}
+
+func (uc *moderationUsecase) AssignItemUsecase(reqBody *ReqBodyAssignItem, itemId, userId int) (*models.ModerationItem, error) {
+	if itemId <= 0 {
+		return nil, fmt.Errorf("invalid item id")
+	}
+	if err := uc.requireModerator(userId); err != nil {
+		return nil, err
+	}
+
+	assigneeId := reqBody.AssigneeId
+	if assigneeId == 0 {
+		assigneeId = uint(userId)
+	} else if err := uc.requireModerator(int(assigneeId)); err != nil {
+		return nil, fmt.Errorf("items can only be assigned to moderators")
+	}
+
+	item, err := uc.moderationRepo.GetItem(itemId)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status != models.ModerationStatusPending {
+		return nil, fmt.Errorf("item is already resolved")
+	}
+
+	item.AssigneeId = &assigneeId
+	return uc.moderationRepo.UpdateItem(item, map[string]interface{}{"assignee_id": assigneeId})
+}
+
+// ResolveItemUsecase closes an item. Dismissing brings back a target the
+// item hid, hiding keeps the target out of sight and suspending also locks
+// out its author.
+func (uc *moderationUsecase) ResolveItemUsecase(reqBody *ReqBodyResolveItem, itemId, userId int) (*models.ModerationItem, error) {
+	if itemId <= 0 {
+		return nil, fmt.Errorf("invalid item id")
+	}
+	if reqBody.SuspendDays < 0 {
+		return nil, fmt.Errorf("suspend days cannot be negative")
+	}
+	if err := uc.requireModerator(userId); err != nil {
+		return nil, err
+	}
+
+	var item *models.ModerationItem
+	err := uc.inTransaction(func(tx *moderationUsecase) error {
+		var err error
+		item, err = tx.moderationRepo.GetItem(itemId)
+		if err != nil {
+			return err
+		}
+		if item.Status != models.ModerationStatusPending {
+			return fmt.Errorf("item is already resolved")
+		}
+
+		now := time.Now()
+		status := models.ModerationStatusActioned
+		targetHidden := item.TargetHidden
+		switch reqBody.Resolution {
+		case models.ModerationResolutionDismiss:
+			status = models.ModerationStatusDismissed
+			if item.TargetHidden {
+				if _, err := tx.moderationRepo.SetTargetHidden(item.TargetType, item.TargetId, nil); err != nil {
+					return err
+				}
+				targetHidden = false
+			}
+		case models.ModerationResolutionHide, models.ModerationResolutionSuspend:
+			if reqBody.Resolution == models.ModerationResolutionSuspend {
+				if err := tx.suspend(item.AuthorId, now, reqBody.SuspendDays); err != nil {
+					return err
+				}
+			} else if item.TargetType == models.ModerationTargetUser {
+				return fmt.Errorf("users can't be hidden, suspend them instead")
+			}
+			if item.TargetType != models.ModerationTargetUser {
+				hidden, err := tx.moderationRepo.SetTargetHidden(item.TargetType, item.TargetId, &now)
+				if err != nil {
+					return err
+				}
+				targetHidden = targetHidden || hidden
+			}
+		default:
+			return fmt.Errorf("unknown resolution %q", reqBody.Resolution)
+		}
+
+		resolvedBy := uint(userId)
+		item.Status = status
+		item.Resolution = reqBody.Resolution
+		item.ResolvedBy = &resolvedBy
+		item.ResolvedAt = &now
+		item.Note = strings.TrimSpace(reqBody.Note)
+		item.TargetHidden = targetHidden
+		_, err = tx.moderationRepo.UpdateItem(item, map[string]interface{}{
+			"status":        item.Status,
+			"resolution":    item.Resolution,
+			"resolved_by":   resolvedBy,
+			"resolved_at":   now,
+			"note":          item.Note,
+			"target_hidden": targetHidden,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (uc *moderationUsecase) suspend(userId uint, now time.Time, days int) error {
+	user, err := uc.userRepo.GetUserById(int64(userId))
+	if err != nil {
+		return err
+	}
+	if user.Role == models.UserRoleModerator {
+		return fmt.Errorf("moderators can't be suspended")
+	}
+
+	var until *time.Time
+	if days > 0 {
+		end := now.AddDate(0, 0, days)
+		until = &end
+	}
+	_, err = uc.userRepo.SuspendUser(&user, now, until)
+	return err
+}
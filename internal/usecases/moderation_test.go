@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"testing"
+	"together-backend/internal/models"
+)
+
+func TestReportAutoHide(t *testing.T) {
+	tests := []struct {
+		name       string
+		threshold  int
+		reporters  []int
+		targetType string
+		hidden     bool
+		wantHidden bool
+	}{
+		{"below the threshold", 3, []int{2, 3}, models.ModerationTargetComment, false, false},
+		{"at the threshold", 3, []int{2, 3, 4}, models.ModerationTargetComment, false, true},
+		{"events too", 2, []int{2, 3}, models.ModerationTargetEvent, false, true},
+		{"disabled", 0, []int{2, 3, 4, 5}, models.ModerationTargetComment, false, false},
+		{"users are never hidden", 1, []int{2, 3}, models.ModerationTargetUser, false, false},
+		{"already hidden", 1, []int{2}, models.ModerationTargetComment, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeModerationRepo{}
+			if tt.hidden {
+				repo.items = append(repo.items, &models.ModerationItem{Id: 1, TargetType: tt.targetType, TargetId: 7, TargetHidden: true})
+			}
+			uc := &moderationUsecase{moderationRepo: repo, autoHideReports: tt.threshold}
+
+			var item *models.ModerationItem
+			for _, userId := range tt.reporters {
+				var err error
+				item, err = uc.report(&models.ModerationItem{TargetType: tt.targetType, TargetId: 7, AuthorId: 1}, &ReqBodyReport{Category: "spam"}, userId)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if item.ReportCount != int64(len(tt.reporters)) {
+				t.Errorf("item has %d reports, want %d", item.ReportCount, len(tt.reporters))
+			}
+			if hidden := len(repo.hidden) > 0; hidden != tt.wantHidden {
+				t.Errorf("target hidden %v, want %v", hidden, tt.wantHidden)
+			}
+			if tt.wantHidden && (len(repo.hidden) != 1 || !item.TargetHidden) {
+				t.Errorf("target hidden %d times and item marked %v, want once and marked", len(repo.hidden), item.TargetHidden)
+			}
+		})
+	}
+}
+
+func TestReportRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  ReqBodyReport
+		reporter int
+	}{
+		{"unknown category", ReqBodyReport{Category: "boring"}, 2},
+		{"own content", ReqBodyReport{Category: "spam"}, 1},
+		{"twice", ReqBodyReport{Category: "spam"}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeModerationRepo{
+				items:     []*models.ModerationItem{{Id: 1, TargetType: models.ModerationTargetComment, TargetId: 7}},
+				reporters: map[uint][]int{1: {3}},
+			}
+			uc := &moderationUsecase{moderationRepo: repo, autoHideReports: 1}
+			_, err := uc.report(&models.ModerationItem{TargetType: models.ModerationTargetComment, TargetId: 7, AuthorId: 1}, &tt.reqBody, tt.reporter)
+			if err == nil {
+				t.Error("report succeeded, want an error")
+			}
+			if len(repo.hidden) > 0 {
+				t.Error("a rejected report hid its target")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"fmt"
+	"together-backend/internal/models"
+	"together-backend/internal/repositories"
+)
+
+type NotificationUseCase interface {
+	GetNotificationsUsecase(userId int, unreadOnly bool, pagination *repositories.Pagination) ([]models.Notification, *repositories.PageInfo, int64, error)
+	MarkReadUsecase(userId int, ids []int) (int64, error)
+}
+
+type notificationUsecase struct {
+	notificationRepo repositories.NotificationRepo
+}
+
+type ReqBodyMarkRead struct {
+	Ids []int `json:"ids"`
+}
+
+func NewNotificationUsecase(notificationRepo repositories.NotificationRepo) NotificationUseCase {
+	return &notificationUsecase{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (uc *notificationUsecase) GetNotificationsUsecase(userId int, unreadOnly bool, pagination *repositories.Pagination) ([]models.Notification, *repositories.PageInfo, int64, error) {
+	if userId <= 0 {
+		return nil, nil, int64(0), fmt.Errorf("invalid user id")
+	}
+	pagination.Normalize()
+
+	unread, err := uc.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return nil, nil, int64(0), err
+	}
+
+	notifications, pageInfo, err := uc.notificationRepo.GetNotifications(userId, unreadOnly, pagination)
+	if err != nil {
+		return nil, nil, int64(0), err
+	}
+
+	return notifications, pageInfo, unread, nil
+}
+
+func (uc *notificationUsecase) MarkReadUsecase(userId int, ids []int) (int64, error) {
+	if userId <= 0 {
+		return int64(0), fmt.Errorf("invalid user id")
+	}
+
+	return uc.notificationRepo.MarkRead(userId, ids)
+}
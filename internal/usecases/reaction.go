@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"fmt"
+	"together-backend/internal/models"
+)
+
+type ReqBodyReaction struct {
+	Emoji string `json:"emoji"`
+}
+
+func (uc *eventUsecase) ToggleEventReactionUsecase(reqBody *ReqBodyReaction, eventId, userId int) ([]models.ReactionCount, bool, error) {
+	if eventId <= 0 {
+		return nil, false, fmt.Errorf("invalid event id")
+	}
+	if !models.IsReactionEmoji(reqBody.Emoji) {
+		return nil, false, fmt.Errorf("unsupported reaction %q", reqBody.Emoji)
+	}
+
+	event, err := uc.visibleEvent(eventId, userId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	added, err := uc.reactionRepo.ToggleReaction(models.ReactionTargetEvent, event.Id, userId, reqBody.Emoji)
+	if err != nil {
+		return nil, false, err
+	}
+
+	counts, err := uc.reactionRepo.GetReactionCounts(models.ReactionTargetEvent, []uint{event.Id}, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	return reactionCounts(counts[event.Id]), added, nil
+}
+
+// reactionCounts keeps "no reactions" an empty list in responses.
+func reactionCounts(counts []models.ReactionCount) []models.ReactionCount {
+	if counts == nil {
+		return []models.ReactionCount{}
+	}
+	return counts
+}
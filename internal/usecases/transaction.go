@@ -0,0 +1,22 @@
+package usecases
+
+import "together-backend/internal/repositories"
+
+// transactional is a usecase that can be copied onto the repositories of
+// one transaction. The copy has no unit of work, so its own transactions
+// join the caller's.
+type transactional interface {
+	withRepositories(repos *repositories.Repositories) transactional
+}
+
+// inTransaction runs fn with a copy of uc bound to one transaction of uow,
+// so its steps commit or roll back together. Without a unit of work fn runs
+// on uc itself.
+func inTransaction(uow repositories.UnitOfWork, uc transactional, fn func(tx transactional) error) error {
+	if uow == nil {
+		return fn(uc)
+	}
+	return uow.Do(func(repos *repositories.Repositories) error {
+		return fn(uc.withRepositories(repos))
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"mime/multipart"
+	"path"
+	"strings"
+	"together-backend/internal/models"
 	"together-backend/internal/repositories"
 	"together-backend/pkg"
 
@@ -16,7 +19,9 @@ const MAX_UPLOAD_AVATAR_SIZE = 5242880 // 5Mb
 
 type UploadUseCase interface {
 	EventImageUpload(files []*multipart.FileHeader) ([]string, error)
+	CommentImageUpload(files []*multipart.FileHeader) ([]string, error)
 	UploadAvatar(file *multipart.FileHeader) (string, error)
+	DeleteImages(urls []string) error
 }
 
 type uploadUsecase struct {
@@ -30,6 +35,16 @@ func NewUploadUsecase(imageRepo repositories.ImageRepo) UploadUseCase {
 }
 
 func (uc *uploadUsecase) EventImageUpload(files []*multipart.FileHeader) ([]string, error) {
+	return uc.uploadImages(files, "events/", 5)
+}
+
+// CommentImageUpload stores the images attached to a comment, with the same
+// size checks as event images.
+func (uc *uploadUsecase) CommentImageUpload(files []*multipart.FileHeader) ([]string, error) {
+	return uc.uploadImages(files, "comments/", models.MaxCommentImages)
+}
+
+func (uc *uploadUsecase) uploadImages(files []*multipart.FileHeader, folder string, maxFiles int) ([]string, error) {
 	for _, fileHeader := range files {
 		if fileHeader.Size > MAX_UPLOAD_SIZE {
 			return nil, fmt.Errorf("the uploaded file is too big. Please choose an file that's less than 10MB in size")
@@ -44,8 +59,8 @@ func (uc *uploadUsecase) EventImageUpload(files []*multipart.FileHeader) ([]stri
 	var ctx = context.Background()
 	var imagesSlice []string
 
-	if len(files) > 5 {
-		return nil, fmt.Errorf("the number of files uploaded is too much. Upload up to 5 files")
+	if len(files) > maxFiles {
+		return nil, fmt.Errorf("the number of files uploaded is too much. Upload up to %d files", maxFiles)
 	}
 	for i, _ := range files {
 		fileName := files[i].Filename
@@ -70,9 +85,11 @@ func (uc *uploadUsecase) EventImageUpload(files []*multipart.FileHeader) ([]stri
 			ctx,
 			file,
 			uploader.UploadParams{
-				PublicID: "events/" + fileName + "_" + pkg.RandomID(6),
+				PublicID: folder + fileName + "_" + pkg.RandomID(6),
 			})
 		if err != nil {
+			// a failed batch keeps none of its images
+			uc.DeleteImages(imagesSlice)
 			return nil, fmt.Errorf("failed to upload file")
 		}
 		imagesSlice = append(imagesSlice, uploadResult.SecureURL)
@@ -112,3 +129,45 @@ func (uc *uploadUsecase) UploadAvatar(file *multipart.FileHeader) (string, error
 
 	return uploadResult.SecureURL, nil
 }
+
+// DeleteImages removes uploaded images from storage. It tries every image
+// and returns the first failure.
+func (uc *uploadUsecase) DeleteImages(urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	var cld, err = cloudinary.New()
+	if err != nil {
+		return fmt.Errorf("failed to intialize Cloudinary")
+	}
+
+	var ctx = context.Background()
+	var firstErr error
+	for _, url := range urls {
+		publicId := publicIdOf(url)
+		if publicId == "" {
+			continue
+		}
+		_, err := cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicId})
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to delete file %s", publicId)
+		}
+	}
+	return firstErr
+}
+
+// publicIdOf reads the public id back from a delivery URL such as
+// https://res.cloudinary.com/demo/image/upload/v1712/comments/a.png_x1y2z3.png,
+// which is the path after the version without the format extension.
+func publicIdOf(url string) string {
+	i := strings.Index(url, "/upload/")
+	if i < 0 {
+		return ""
+	}
+	id := url[i+len("/upload/"):]
+	if slash := strings.IndexByte(id, '/'); slash > 1 && id[0] == 'v' && strings.Trim(id[1:slash], "0123456789") == "" {
+		id = id[slash+1:]
+	}
+	return strings.TrimSuffix(id, path.Ext(id))
+}
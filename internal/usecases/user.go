@@ -10,7 +10,7 @@ import (
 
 type UserCase interface {
 	GetUserDetailUsecase(userId int) (*models.User, error)
-	UpdateProfilelUsecase(userId int, name string, address int, avatarUrl string) (*models.User, error)
+	UpdateProfilelUsecase(userId int, name string, address int, avatarUrl string, version int) (*models.User, error)
 	ChangePasswordUsecase(userId int, oldPassword, newPassword, passwordConfirm string) (*models.User, error)
 }
 
@@ -43,7 +43,7 @@ func (uc *userUsecase) GetUserDetailUsecase(userId int) (*models.User, error) {
 	return user, nil
 }
 
-func (uc *userUsecase) UpdateProfilelUsecase(userId int, name string, address int, avatarUrl string) (*models.User, error) {
+func (uc *userUsecase) UpdateProfilelUsecase(userId int, name string, address int, avatarUrl string, version int) (*models.User, error) {
 
 	var (
 		user        *models.User
@@ -64,6 +64,9 @@ func (uc *userUsecase) UpdateProfilelUsecase(userId int, name string, address in
 	if err != nil {
 		return nil, err
 	}
+	if user.Version != version {
+		return nil, repositories.ErrVersionConflict
+	}
 
 	if avatarUrl == "" {
 		updatedUser, err = uc.userRepo.UpdateProfile(user, name, address)
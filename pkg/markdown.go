@@ -0,0 +1,520 @@
+package pkg
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RenderMarkdown renders a subset of Markdown to HTML that is safe to embed.
+// All input text is escaped, raw HTML included, and only these tags are
+// emitted: p, br, h1-h6, strong, em, del, code, pre, blockquote, ul, ol, li,
+// hr and a. Links keep relative, http, https and mailto URLs only and get
+// rel="nofollow".
+func RenderMarkdown(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"), false, 0)
+	return b.String()
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ ]+(.*?))??(?:[ ]+#+)?[ ]*$`)
+	rulePattern    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ ]*){3,}|(?:-[ ]*){3,}|(?:_[ ]*){3,})$`)
+	fencePattern   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})")
+	listPattern    = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])(?:[ ]+(.*))?$`)
+)
+
+// maxNesting bounds how deep quotes and lists nest; deeper markers are
+// rendered as text.
+const maxNesting = 16
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// quoted returns the text after a quote marker. Nested quotes strip one
+// marker per level, so this stays a slice rather than a regexp match.
+func quoted(line string) (string, bool) {
+	n := indentOf(line)
+	if n > 3 || n >= len(line) || line[n] != '>' {
+		return "", false
+	}
+	return strings.TrimPrefix(line[n+1:], " "), true
+}
+
+func isQuote(line string) bool {
+	_, ok := quoted(line)
+	return ok
+}
+
+// startsBlock tells whether line interrupts a paragraph.
+func startsBlock(line string) bool {
+	if headingPattern.MatchString(line) || rulePattern.MatchString(line) || fencePattern.MatchString(line) || isQuote(line) {
+		return true
+	}
+	m := listPattern.FindStringSubmatch(line)
+	if m == nil || m[3] == "" {
+		return false
+	}
+	// only lists starting at 1 interrupt, so "2024. was great" stays text
+	if n := strings.TrimRight(m[2], ".)"); n != m[2] {
+		return n == "1"
+	}
+	return true
+}
+
+// renderBlocks renders lines as block elements. In tight lists paragraphs
+// are written without their p tags.
+func renderBlocks(b *strings.Builder, lines []string, tight bool, depth int) {
+	nest := depth < maxNesting
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case fencePattern.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">")
+			renderInline(b, strings.TrimSpace(m[2]), true)
+			b.WriteString("</h" + level + ">\n")
+			i++
+		case rulePattern.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case nest && isQuote(line):
+			var inner []string
+			for ; i < len(lines); i++ {
+				text, ok := quoted(lines[i])
+				if !ok {
+					break
+				}
+				inner = append(inner, text)
+			}
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, inner, false, depth+1)
+			b.WriteString("</blockquote>\n")
+		case nest && listPattern.MatchString(line):
+			i = renderList(b, lines, i, depth)
+		default:
+			var paragraph []string
+			for ; i < len(lines) && !isBlank(lines[i]); i++ {
+				if len(paragraph) > 0 && nest && startsBlock(lines[i]) {
+					break
+				}
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			if !tight {
+				b.WriteString("<p>")
+			}
+			for j, text := range paragraph {
+				if j > 0 {
+					b.WriteString("<br>\n")
+				}
+				renderInline(b, text, true)
+			}
+			if !tight {
+				b.WriteString("</p>")
+			}
+			b.WriteString("\n")
+		}
+	}
+}
+
+func renderFence(b *strings.Builder, lines []string, start int) int {
+	m := fencePattern.FindStringSubmatch(lines[start])
+	indent, fence := len(m[1]), m[2]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		if n := indentOf(line); n < indent {
+			line = line[n:]
+		} else {
+			line = line[indent:]
+		}
+		code = append(code, line)
+	}
+
+	b.WriteString("<pre><code>")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+// renderList renders the list starting at lines[start] and returns the
+// index of the first line after it. Lines indented past the marker belong
+// to the item, which is how lists nest.
+func renderList(b *strings.Builder, lines []string, start, depth int) int {
+	m := listPattern.FindStringSubmatch(lines[start])
+	indent := len(m[1])
+	ordered := m[2] != "-" && m[2] != "*" && m[2] != "+"
+	sameList := func(marker string) bool {
+		if ordered {
+			return marker[len(marker)-1] == m[2][len(m[2])-1]
+		}
+		return marker == m[2]
+	}
+
+	var (
+		items [][]string
+		loose bool
+		i     = start
+	)
+	for i < len(lines) {
+		line := lines[i]
+		if item := listPattern.FindStringSubmatch(line); item != nil && len(item[1]) <= indent+1 {
+			if !sameList(item[2]) {
+				break
+			}
+			items = append(items, []string{item[3]})
+			i++
+			continue
+		}
+		last := len(items) - 1
+		if isBlank(line) {
+			j := i
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			next := j < len(lines) && indentOf(lines[j]) >= indent+2
+			if j < len(lines) && !next {
+				item := listPattern.FindStringSubmatch(lines[j])
+				next = item != nil && len(item[1]) <= indent+1 && sameList(item[2])
+			}
+			if !next {
+				break
+			}
+			loose = true
+			for ; i < j; i++ {
+				items[last] = append(items[last], "")
+			}
+			continue
+		}
+		if n := indentOf(line); n >= indent+2 {
+			dedent := indent + len(m[2]) + 1
+			if n < dedent {
+				dedent = n
+			}
+			items[last] = append(items[last], line[dedent:])
+			i++
+			continue
+		}
+		// a lazy continuation of the item's text
+		if isBlank(items[last][len(items[last])-1]) || startsBlock(line) {
+			break
+		}
+		items[last] = append(items[last], strings.TrimSpace(line))
+		i++
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+		if n, _ := strconv.Atoi(strings.TrimRight(m[2], ".)")); n != 1 {
+			b.WriteString(`<ol start="` + strconv.Itoa(n) + `">` + "\n")
+		} else {
+			b.WriteString("<ol>\n")
+		}
+	} else {
+		b.WriteString("<ul>\n")
+	}
+	for _, item := range items {
+		b.WriteString("<li>")
+		var inner strings.Builder
+		renderBlocks(&inner, item, !loose, depth+1)
+		b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+var (
+	autolinkPattern = regexp.MustCompile(`^<((?:https?://|mailto:)[^\s<>]+)>`)
+	bareURLPattern  = regexp.MustCompile(`^(?:https?://|www\.)[^\s<>]+`)
+)
+
+func isPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isWordByte(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if !utf8.RuneStart(text[i]) {
+		r, _ = utf8.DecodeLastRuneInString(text[:i+1])
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// safeURL tells whether a link target can be rendered: relative URLs and
+// the http, https and mailto schemes.
+func safeURL(url string) bool {
+	if url == "" || strings.IndexFunc(url, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return false
+	}
+	end := strings.IndexAny(url, "/?#")
+	if end < 0 {
+		end = len(url)
+	}
+	colon := strings.IndexByte(url[:end], ':')
+	if colon < 0 {
+		return true
+	}
+	switch strings.ToLower(url[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func writeLink(b *strings.Builder, url string) {
+	b.WriteString(`<a href="`)
+	b.WriteString(html.EscapeString(url))
+	b.WriteString(`" rel="nofollow">`)
+}
+
+// inlineState remembers delimiters that have no closer left, so runs of
+// unmatched markers stay linear.
+type inlineState struct {
+	noCloser map[string]bool
+}
+
+func renderInline(b *strings.Builder, text string, links bool) {
+	s := &inlineState{noCloser: make(map[string]bool)}
+	s.render(b, text, links)
+}
+
+// matchPairs pairs every [ with its ] and every ( with its ) in one pass,
+// skipping escaped characters. Unmatched openers are left out, so finding a
+// link never rescans the text.
+func matchPairs(text string) map[int]int {
+	pairs := make(map[int]int)
+	var brackets, parens []int
+	for j := 0; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			brackets = append(brackets, j)
+		case '(':
+			parens = append(parens, j)
+		case ']':
+			if n := len(brackets); n > 0 {
+				pairs[brackets[n-1]] = j
+				brackets = brackets[:n-1]
+			}
+		case ')':
+			if n := len(parens); n > 0 {
+				pairs[parens[n-1]] = j
+				parens = parens[:n-1]
+			}
+		}
+	}
+	return pairs
+}
+
+func (s *inlineState) render(b *strings.Builder, text string, links bool) {
+	var pairs map[int]int
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && isPunct(text[i+1]):
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if end, ok := s.codeSpan(b, text, i); ok {
+				i = end
+				continue
+			}
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+			b.WriteString(text[i : i+n])
+			i += n
+			continue
+		case c == '*' || c == '_' || c == '~':
+			if end, ok := s.emphasis(b, text, i, links); ok {
+				i = end
+				continue
+			}
+		case c == '[' && links:
+			if pairs == nil {
+				pairs = matchPairs(text)
+			}
+			if end, ok := s.link(b, text, i, pairs); ok {
+				i = end
+				continue
+			}
+		case c == '<' && links:
+			if m := autolinkPattern.FindStringSubmatch(text[i:]); m != nil {
+				writeLink(b, m[1])
+				b.WriteString(html.EscapeString(m[1]))
+				b.WriteString("</a>")
+				i += len(m[0])
+				continue
+			}
+		case (c == 'h' || c == 'w') && links && !isWordByte(text, i-1):
+			if end, ok := bareURL(b, text, i); ok {
+				i = end
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(html.EscapeString(text[i : i+size]))
+		i += size
+	}
+}
+
+func (s *inlineState) codeSpan(b *strings.Builder, text string, start int) (int, bool) {
+	n := len(text[start:]) - len(strings.TrimLeft(text[start:], "`"))
+	fence := text[start : start+n]
+	if s.noCloser[fence] {
+		return 0, false
+	}
+	for j := start + n; j < len(text); {
+		k := strings.Index(text[j:], fence)
+		if k < 0 {
+			break
+		}
+		k += j
+		m := len(text[k:]) - len(strings.TrimLeft(text[k:], "`"))
+		if m != n {
+			j = k + m
+			continue
+		}
+		code := text[start+n : k]
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		b.WriteString("<code>")
+		b.WriteString(html.EscapeString(code))
+		b.WriteString("</code>")
+		return k + n, true
+	}
+	s.noCloser[fence] = true
+	return 0, false
+}
+
+// emphasis renders **strong**, __strong__, *em*, _em_ and ~~del~~. Markers
+// must hug their text, and underscores inside words stay literal so
+// snake_case names survive.
+func (s *inlineState) emphasis(b *strings.Builder, text string, start int, links bool) (int, bool) {
+	c := text[start]
+	n := 1
+	if start+1 < len(text) && text[start+1] == c {
+		n = 2
+	}
+	if c == '~' && n != 2 {
+		return 0, false
+	}
+	marker := text[start : start+n]
+	if s.noCloser[marker] || start+n >= len(text) || text[start+n] == ' ' {
+		return 0, false
+	}
+	if c == '_' && isWordByte(text, start-1) {
+		return 0, false
+	}
+
+	for j := start + n + 1; j < len(text); j++ {
+		k := strings.Index(text[j:], marker)
+		if k < 0 {
+			break
+		}
+		k += j
+		j = k
+		// a single marker must not be half of a double one
+		if n == 1 && (k+1 < len(text) && text[k+1] == c || text[k-1] == c) {
+			continue
+		}
+		if text[k-1] == ' ' || c == '_' && isWordByte(text, k+n) {
+			continue
+		}
+
+		tag := map[byte]string{'*': "em", '_': "em", '~': "del"}[c]
+		if n == 2 && c != '~' {
+			tag = "strong"
+		}
+		b.WriteString("<" + tag + ">")
+		s.render(b, text[start+n:k], links)
+		b.WriteString("</" + tag + ">")
+		return k + n, true
+	}
+	s.noCloser[marker] = true
+	return 0, false
+}
+
+// link renders [text](url), with brackets and parentheses paired by
+// matchPairs. Links with unsafe targets keep their text only.
+func (s *inlineState) link(b *strings.Builder, text string, start int, pairs map[int]int) (int, bool) {
+	closing, ok := pairs[start]
+	if !ok || closing+1 >= len(text) || text[closing+1] != '(' {
+		return 0, false
+	}
+	end, ok := pairs[closing+1]
+	if !ok {
+		return 0, false
+	}
+
+	target := strings.TrimSpace(text[closing+2 : end])
+	// drop an optional title
+	if k := strings.IndexAny(target, " \""); k >= 0 {
+		target = target[:k]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+
+	label := text[start+1 : closing]
+	if safeURL(target) {
+		writeLink(b, target)
+		s.render(b, label, false)
+		b.WriteString("</a>")
+	} else {
+		s.render(b, label, false)
+	}
+	return end + 1, true
+}
+
+func bareURL(b *strings.Builder, text string, start int) (int, bool) {
+	url := bareURLPattern.FindString(text[start:])
+	if url == "" {
+		return 0, false
+	}
+	// trailing punctuation ends the sentence rather than the link
+	url = strings.TrimRight(url, ".,;:!?'\"*_~")
+	for strings.HasSuffix(url, ")") && strings.Count(url, ")") > strings.Count(url, "(") {
+		url = strings.TrimSuffix(url, ")")
+	}
+
+	href := url
+	if strings.HasPrefix(href, "www.") {
+		href = "http://" + href
+	}
+	writeLink(b, href)
+	b.WriteString(html.EscapeString(url))
+	b.WriteString("</a>")
+	return start + len(url), true
+}
@@ -0,0 +1,179 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"paragraph", "hello\nworld", "<p>hello<br>\nworld</p>\n"},
+		{"heading", "## Agenda ##", "<h2>Agenda</h2>\n"},
+		{"emphasis", "**bold** *em* ~~del~~", "<p><strong>bold</strong> <em>em</em> <del>del</del></p>\n"},
+		{"intraword underscore", "snake_case_name", "<p>snake_case_name</p>\n"},
+		{"code span", "`a<b`", "<p><code>a&lt;b</code></p>\n"},
+		{"fence", "```\n<x>\n```", "<pre><code>&lt;x&gt;\n</code></pre>\n"},
+		{"quote", "> quoted", "<blockquote>\n<p>quoted</p>\n</blockquote>\n"},
+		{"list", "- a\n- b\n  - c", "<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul></li>\n</ul>\n"},
+		{"ordered list", "3. three\n4. four", "<ol start=\"3\">\n<li>three</li>\n<li>four</li>\n</ol>\n"},
+		{"rule", "***", "<hr>\n"},
+		{"link", "[site](https://example.com/a?b=1&c=2)", "<p><a href=\"https://example.com/a?b=1&amp;c=2\" rel=\"nofollow\">site</a></p>\n"},
+		{"stray bracket before link", "[[a](x)", "<p>[<a href=\"x\" rel=\"nofollow\">a</a></p>\n"},
+		{"parens in url", "[a](x(y)z)", "<p><a href=\"x(y)z\" rel=\"nofollow\">a</a></p>\n"},
+		{"unclosed url", "[a]((x)", "<p>[a]((x)</p>\n"},
+		{"relative link", "[rel](/events/3)", "<p><a href=\"/events/3\" rel=\"nofollow\">rel</a></p>\n"},
+		{"autolink", "<https://a.b>", "<p><a href=\"https://a.b\" rel=\"nofollow\">https://a.b</a></p>\n"},
+		{"bare url", "see www.x.org.", "<p>see <a href=\"http://www.x.org\" rel=\"nofollow\">www.x.org</a>.</p>\n"},
+		{"escape", "\\*lit\\*", "<p>*lit*</p>\n"},
+		{"raw html", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+		{"javascript link", "[click](javascript:alert(1))", "<p>click</p>\n"},
+		{"data link", "[click](data:text/html;base64,PHNjcmlwdD4=)", "<p>click</p>\n"},
+		{"quote in url", "[x](https://a.b/\"onmouseover=alert(1))", "<p><a href=\"https://a.b/\" rel=\"nofollow\">x</a></p>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdown(tt.source); got != tt.want {
+				t.Errorf("RenderMarkdown(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+var (
+	tagPattern       = regexp.MustCompile(`<(/?)([a-zA-Z0-9]+)((?:\s+[^>]*)?)>`)
+	attributePattern = regexp.MustCompile(`\s+([a-z]+)="([^"]*)"`)
+	allowedTags      = map[string]bool{
+		"p": true, "br": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+		"strong": true, "em": true, "del": true, "code": true, "pre": true, "blockquote": true,
+		"ul": true, "ol": true, "li": true, "hr": true, "a": true,
+	}
+)
+
+// TestRenderMarkdownAllowlist checks that hostile input only ever yields
+// allowlisted tags and attributes, and links that are safe and nofollow.
+func TestRenderMarkdownAllowlist(t *testing.T) {
+	sources := []string{
+		`<img src=x onerror=alert(1)>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`[x](javascript:alert(1))`,
+		`[x](JaVaScRiPt:alert(1))`,
+		`[x](vbscript:msgbox)`,
+		`[x](java	script:alert(1))`,
+		`[x](<javascript:alert(1)>)`,
+		`<javascript:alert(1)>`,
+		`[x](https://a.b" onclick="alert(1))`,
+		`[x](https://a.b'onclick='alert(1))`,
+		`**<b>**` + "\n\n" + "`</code><script>`",
+		"> <iframe src=//evil>\n> - <svg onload=alert(1)>",
+		"```\n</pre><script>alert(1)</script>\n```",
+		`www.x.org/"><script>`,
+		`https://a.b/<script>`,
+		"[" + strings.Repeat("[", 50) + "x](http://a)" + strings.Repeat("]", 50),
+		strings.Repeat("> ", 100) + "<script>",
+		strings.Repeat("- ", 100) + "<script>",
+	}
+	for _, source := range sources {
+		got := RenderMarkdown(source)
+		rest := tagPattern.ReplaceAllStringFunc(got, func(tag string) string {
+			m := tagPattern.FindStringSubmatch(tag)
+			if !allowedTags[m[2]] {
+				t.Errorf("RenderMarkdown(%q) emitted tag %q", source, tag)
+			}
+			attributes := attributePattern.FindAllStringSubmatch(m[3], -1)
+			if strings.TrimSpace(attributePattern.ReplaceAllString(m[3], "")) != "" {
+				t.Errorf("RenderMarkdown(%q) emitted malformed attributes in %q", source, tag)
+			}
+			for _, attribute := range attributes {
+				switch attribute[1] {
+				case "href":
+					if m[2] != "a" || !safeURL(attribute[2]) {
+						t.Errorf("RenderMarkdown(%q) emitted unsafe href in %q", source, tag)
+					}
+				case "rel":
+					if attribute[2] != "nofollow" {
+						t.Errorf("RenderMarkdown(%q) emitted rel %q", source, attribute[2])
+					}
+				case "start":
+				default:
+					t.Errorf("RenderMarkdown(%q) emitted attribute %q", source, attribute[1])
+				}
+			}
+			if m[2] == "a" && m[1] == "" && !strings.Contains(tag, `rel="nofollow"`) {
+				t.Errorf("RenderMarkdown(%q) emitted a link without nofollow: %q", source, tag)
+			}
+			return ""
+		})
+		if strings.ContainsAny(rest, "<>") {
+			t.Errorf("RenderMarkdown(%q) left unescaped markup: %q", source, got)
+		}
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com/a:b", true},
+		{"HTTPS://example.com", true},
+		{"mailto:someone@example.com", true},
+		{"/events/3", true},
+		{"events/3?at=10:00", true},
+		{"#agenda", true},
+		{"", false},
+		{"javascript:alert(1)", false},
+		{"JavaScript:alert(1)", false},
+		{"vbscript:msgbox", false},
+		{"data:text/html,<script>", false},
+		{"file:///etc/passwd", false},
+		{"java\tscript:alert(1)", false},
+		{"java\nscript:alert(1)", false},
+		{" javascript:alert(1)", false},
+		{"https://a.b/\x00", false},
+	}
+	for _, tt := range tests {
+		if got := safeURL(tt.url); got != tt.want {
+			t.Errorf("safeURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+// TestRenderMarkdownAdversarial renders inputs five times the longest event
+// content that once made the renderer quadratic. Linear rendering takes a
+// few milliseconds each, quadratic rendering seconds.
+func TestRenderMarkdownAdversarial(t *testing.T) {
+	const n = 5 * 20000
+	const budget = 250 * time.Millisecond
+
+	var fences strings.Builder
+	for i := 1; fences.Len() < n; i++ {
+		fences.WriteString(strings.Repeat("`", i) + "a")
+	}
+	sources := map[string]string{
+		"unmatched brackets": strings.Repeat("[", n/2) + strings.Repeat("]", n/2),
+		"unclosed links":     strings.Repeat("[a](", n/4),
+		"labels":             strings.Repeat("[a]", n/3),
+		"unclosed emphasis":  strings.Repeat("*a ", n/3),
+		"strong runs":        strings.Repeat("**a", n/3),
+		"mixed markers":      strings.Repeat("*_**__~~", n/8),
+		"code fences":        fences.String(),
+		"autolinks":          strings.Repeat("<http://a", n/9),
+		"quote markers":      strings.Repeat(">", n),
+		"list lines":         strings.Repeat("- a\n  ", n/6),
+		"bracket lines":      strings.Repeat("[\n", n/2),
+	}
+	for name, source := range sources {
+		start := time.Now()
+		RenderMarkdown(source)
+		if elapsed := time.Since(start); elapsed > budget {
+			t.Errorf("%s: rendering %d bytes took %s, want under %s", name, len(source), elapsed, budget)
+		}
+	}
+}
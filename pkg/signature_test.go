@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	t.Setenv("SECRET_KEY", "secret")
+	signature := Sign("12", "34")
+	tampered := []byte(signature)
+	tampered[0] ^= 1
+	tests := []struct {
+		name      string
+		signature string
+		parts     []string
+		want      bool
+	}{
+		{"valid", signature, []string{"12", "34"}, true},
+		{"other event", signature, []string{"13", "34"}, false},
+		{"other user", signature, []string{"12", "35"}, false},
+		{"joined parts", signature, []string{"1234"}, false},
+		{"missing part", signature, []string{"12"}, false},
+		{"tampered signature", string(tampered), []string{"12", "34"}, false},
+		{"empty signature", "", []string{"12", "34"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.signature, tt.parts...); got != tt.want {
+				t.Errorf("VerifySignature(%q, %q) = %v, want %v", tt.signature, tt.parts, got, tt.want)
+			}
+		})
+	}
+
+	if Sign("12", "34") != signature {
+		t.Error("Sign is not deterministic")
+	}
+
+	// the key is derived from SECRET_KEY, not SECRET_KEY itself
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("12.34"))
+	if signature == base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) {
+		t.Error("Sign keys the HMAC with SECRET_KEY directly")
+	}
+
+	t.Setenv("SECRET_KEY", "other")
+	if VerifySignature(signature, "12", "34") {
+		t.Error("a signature verifies under another SECRET_KEY")
+	}
+}
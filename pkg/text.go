@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// FoldRune lowercases r and strips its diacritics, so "Đ", "đ" and "d" or
+// "Ệ" and "e" compare equal. Combining marks fold to 0 and should be
+// skipped.
+func FoldRune(r rune) rune {
+	switch r {
+	case 'đ', 'Đ':
+		return 'd'
+	}
+	if unicode.Is(unicode.Mn, r) {
+		return 0
+	}
+	for _, base := range norm.NFD.String(string(r)) {
+		return unicode.ToLower(base)
+	}
+	return unicode.ToLower(r)
+}
+
+// Fold applies FoldRune to every rune of s.
+func Fold(s string) string {
+	folded := make([]rune, 0, len(s))
+	for _, r := range s {
+		if f := FoldRune(r); f != 0 {
+			folded = append(folded, f)
+		}
+	}
+	return string(folded)
+}
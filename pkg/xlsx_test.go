@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestXLSXWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Ada", `<c r="A1" t="inlineStr"><is><t xml:space="preserve">Ada</t></is></c>`},
+		{"formula", "=1+1", `<c r="A1" t="inlineStr"><is><t xml:space="preserve">=1+1</t></is></c>`},
+		{"plus", "+1", `<c r="A1" t="inlineStr"><is><t xml:space="preserve">+1</t></is></c>`},
+		{"minus", "-1", `<c r="A1" t="inlineStr"><is><t xml:space="preserve">-1</t></is></c>`},
+		{"at", "@SUM(A1)", `<c r="A1" t="inlineStr"><is><t xml:space="preserve">@SUM(A1)</t></is></c>`},
+		{"markup", `</t><f>HYPERLINK("x")</f>`, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">&lt;/t&gt;&lt;f&gt;HYPERLINK(&#34;x&#34;)&lt;/f&gt;</t></is></c>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet := writeSheet(t, "Roster", []string{tt.value})
+			if !strings.Contains(sheet, tt.want) {
+				t.Errorf("sheet %q does not contain %q", sheet, tt.want)
+			}
+			if strings.Contains(sheet, "<f>") {
+				t.Errorf("sheet %q contains a formula", sheet)
+			}
+		})
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"}, {25, "Z"}, {26, "AA"}, {51, "AZ"}, {52, "BA"}, {701, "ZZ"}, {702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := xlsxColumn(tt.index); got != tt.want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+// writeSheet writes records to a workbook and returns its sheet XML.
+func writeSheet(t *testing.T, name string, records ...[]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	x, err := NewXLSXWriter(&buf, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, record := range records {
+		if err := x.Write(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := x.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		sheet, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(sheet)
+	}
+	t.Fatal("workbook has no sheet")
+	return ""
+}